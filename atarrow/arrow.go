@@ -0,0 +1,244 @@
+/*
+Package atarrow converts ActiveTick bars and ticks into Apache Arrow
+record batches.
+
+Bars use BarSchema:
+
+	symbol  utf8
+	time    timestamp[ms, UTC]
+	open    float64
+	high    float64
+	low     float64
+	close   float64
+	volume  int64
+
+Ticks use TickSchema. Trades and quotes share one schema, and the columns
+that do not apply to a tick's type are null. Quotes store their quote
+condition in the first of the four condition slots.
+
+	symbol         utf8
+	type           utf8 ("T" for trades, "Q" for quotes)
+	time           timestamp[ms, UTC]
+	last_price     float64
+	last_size      int64
+	last_exchange  utf8
+	conditions     fixed_size_list<int32>[4]
+	bid_price      float64
+	ask_price      float64
+	bid_size       int64
+	ask_size       int64
+	bid_exchange   utf8
+	ask_exchange   utf8
+
+Record batches must be released by the caller.
+*/
+package atarrow
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/array"
+	"github.com/apache/arrow-go/v18/arrow/memory"
+	"github.com/timpalpant/go-activetick"
+)
+
+var (
+	BarSchema = arrow.NewSchema([]arrow.Field{
+		{Name: "symbol", Type: arrow.BinaryTypes.String},
+		{Name: "time", Type: arrow.FixedWidthTypes.Timestamp_ms},
+		{Name: "open", Type: arrow.PrimitiveTypes.Float64},
+		{Name: "high", Type: arrow.PrimitiveTypes.Float64},
+		{Name: "low", Type: arrow.PrimitiveTypes.Float64},
+		{Name: "close", Type: arrow.PrimitiveTypes.Float64},
+		{Name: "volume", Type: arrow.PrimitiveTypes.Int64},
+	}, nil)
+
+	TickSchema = arrow.NewSchema([]arrow.Field{
+		{Name: "symbol", Type: arrow.BinaryTypes.String},
+		{Name: "type", Type: arrow.BinaryTypes.String},
+		{Name: "time", Type: arrow.FixedWidthTypes.Timestamp_ms},
+		{Name: "last_price", Type: arrow.PrimitiveTypes.Float64, Nullable: true},
+		{Name: "last_size", Type: arrow.PrimitiveTypes.Int64, Nullable: true},
+		{Name: "last_exchange", Type: arrow.BinaryTypes.String, Nullable: true},
+		{Name: "conditions", Type: arrow.FixedSizeListOf(4, arrow.PrimitiveTypes.Int32)},
+		{Name: "bid_price", Type: arrow.PrimitiveTypes.Float64, Nullable: true},
+		{Name: "ask_price", Type: arrow.PrimitiveTypes.Float64, Nullable: true},
+		{Name: "bid_size", Type: arrow.PrimitiveTypes.Int64, Nullable: true},
+		{Name: "ask_size", Type: arrow.PrimitiveTypes.Int64, Nullable: true},
+		{Name: "bid_exchange", Type: arrow.BinaryTypes.String, Nullable: true},
+		{Name: "ask_exchange", Type: arrow.BinaryTypes.String, Nullable: true},
+	}, nil)
+)
+
+func timestamp(t time.Time) arrow.Timestamp {
+	return arrow.Timestamp(t.UnixNano() / int64(time.Millisecond))
+}
+
+// BarBuilder accumulates bars into record batches with BarSchema.
+type BarBuilder struct {
+	b *array.RecordBuilder
+}
+
+func NewBarBuilder(mem memory.Allocator) *BarBuilder {
+	return &BarBuilder{array.NewRecordBuilder(mem, BarSchema)}
+}
+
+func (bb *BarBuilder) Append(symbol string, record *activetick.BarDataRecord) {
+	bb.b.Field(0).(*array.StringBuilder).Append(symbol)
+	bb.b.Field(1).(*array.TimestampBuilder).Append(timestamp(record.Time))
+	bb.b.Field(2).(*array.Float64Builder).Append(record.Open)
+	bb.b.Field(3).(*array.Float64Builder).Append(record.High)
+	bb.b.Field(4).(*array.Float64Builder).Append(record.Low)
+	bb.b.Field(5).(*array.Float64Builder).Append(record.Close)
+	bb.b.Field(6).(*array.Int64Builder).Append(record.Volume)
+}
+
+// NewRecordBatch returns a record batch of the bars appended since
+// the last call, and resets the builder.
+func (bb *BarBuilder) NewRecordBatch() arrow.RecordBatch {
+	return bb.b.NewRecordBatch()
+}
+
+func (bb *BarBuilder) Release() {
+	bb.b.Release()
+}
+
+// TickBuilder accumulates ticks into record batches with TickSchema.
+type TickBuilder struct {
+	b *array.RecordBuilder
+}
+
+func NewTickBuilder(mem memory.Allocator) *TickBuilder {
+	return &TickBuilder{array.NewRecordBuilder(mem, TickSchema)}
+}
+
+func (tb *TickBuilder) Append(symbol string, record *activetick.TickRecord) {
+	fields := tb.b.Fields()
+	fields[0].(*array.StringBuilder).Append(symbol)
+	fields[1].(*array.StringBuilder).Append(string(record.Type))
+	fields[2].(*array.TimestampBuilder).Append(timestamp(record.Time))
+
+	conditions := fields[6].(*array.FixedSizeListBuilder)
+	conditions.Append(true)
+	values := conditions.ValueBuilder().(*array.Int32Builder)
+	for _, cond := range record.Condition {
+		values.Append(int32(cond))
+	}
+
+	trade := record.Type == activetick.TickTypeTrade
+	appendFloat(fields[3], record.LastPrice, trade)
+	appendInt(fields[4], record.LastSize, trade)
+	appendString(fields[5], string(record.LastExchange), trade)
+
+	quote := record.Type == activetick.TickTypeQuote
+	appendFloat(fields[7], record.BidPrice, quote)
+	appendFloat(fields[8], record.AskPrice, quote)
+	appendInt(fields[9], record.BidSize, quote)
+	appendInt(fields[10], record.AskSize, quote)
+	appendString(fields[11], string(record.BidExchange), quote)
+	appendString(fields[12], string(record.AskExchange), quote)
+}
+
+// NewRecordBatch returns a record batch of the ticks appended since
+// the last call, and resets the builder.
+func (tb *TickBuilder) NewRecordBatch() arrow.RecordBatch {
+	return tb.b.NewRecordBatch()
+}
+
+func (tb *TickBuilder) Release() {
+	tb.b.Release()
+}
+
+func appendFloat(b array.Builder, v float64, valid bool) {
+	if valid {
+		b.(*array.Float64Builder).Append(v)
+	} else {
+		b.AppendNull()
+	}
+}
+
+func appendInt(b array.Builder, v int64, valid bool) {
+	if valid {
+		b.(*array.Int64Builder).Append(v)
+	} else {
+		b.AppendNull()
+	}
+}
+
+func appendString(b array.Builder, v string, valid bool) {
+	if valid {
+		b.(*array.StringBuilder).Append(v)
+	} else {
+		b.AppendNull()
+	}
+}
+
+// NewBarRecordBatch converts all bars of resp into a single record batch.
+func NewBarRecordBatch(mem memory.Allocator, symbol string, resp *activetick.BarDataResponse) arrow.RecordBatch {
+	bb := NewBarBuilder(mem)
+	defer bb.Release()
+
+	bb.b.Reserve(len(resp.Records))
+	for _, record := range resp.Records {
+		bb.Append(symbol, record)
+	}
+
+	return bb.NewRecordBatch()
+}
+
+// NewTickRecordBatch converts all ticks of resp into a single record batch.
+func NewTickRecordBatch(mem memory.Allocator, symbol string, resp *activetick.TickDataResponse) arrow.RecordBatch {
+	tb := NewTickBuilder(mem)
+	defer tb.Release()
+
+	tb.b.Reserve(len(resp.Records))
+	for _, record := range resp.Records {
+		tb.Append(symbol, record)
+	}
+
+	return tb.NewRecordBatch()
+}
+
+// ReadTickBatches reads all ticks from it, passing them to fn in record
+// batches of at most batchSize rows. The batch is released after fn
+// returns, so fn must retain it to keep it. batchSize must be positive.
+func ReadTickBatches(mem memory.Allocator, symbol string, it activetick.TickIterator,
+	batchSize int, fn func(arrow.RecordBatch) error) error {
+	if batchSize < 1 {
+		return fmt.Errorf("Invalid batch size: %d", batchSize)
+	}
+
+	tb := NewTickBuilder(mem)
+	defer tb.Release()
+
+	emit := func() error {
+		batch := tb.NewRecordBatch()
+		defer batch.Release()
+		return fn(batch)
+	}
+
+	n := 0
+	for it.Next() {
+		tb.Append(symbol, it.Record())
+		n++
+		if n == batchSize {
+			if err := emit(); err != nil {
+				return err
+			}
+
+			n = 0
+		}
+	}
+
+	if err := it.Err(); err != nil {
+		return err
+	}
+
+	if n > 0 {
+		return emit()
+	}
+
+	return nil
+}
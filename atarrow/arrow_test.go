@@ -0,0 +1,134 @@
+package atarrow
+
+import (
+	"testing"
+	"time"
+
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/array"
+	"github.com/apache/arrow-go/v18/arrow/memory"
+	"github.com/timpalpant/go-activetick"
+)
+
+var start = time.Date(2012, 8, 3, 19, 30, 0, 551e6, time.UTC)
+
+var ticks = []*activetick.TickRecord{
+	{
+		Type:         activetick.TickTypeTrade,
+		Time:         start,
+		LastPrice:    616.55,
+		LastSize:     100,
+		LastExchange: activetick.ExchangeBatsYExchange,
+		Condition:    [4]activetick.TradeCondition{0, 14, 0, 0},
+	},
+	{
+		Type:        activetick.TickTypeQuote,
+		Time:        start.Add(50 * time.Millisecond),
+		BidPrice:    616.5,
+		AskPrice:    616.6,
+		BidSize:     200,
+		AskSize:     300,
+		BidExchange: activetick.ExchangeNasdaqOmx,
+		AskExchange: activetick.ExchangeNyseArcaExchange,
+	},
+	{
+		Type:         activetick.TickTypeTrade,
+		Time:         start.Add(100 * time.Millisecond),
+		LastPrice:    616.6,
+		LastSize:     300,
+		LastExchange: activetick.ExchangeNasdaqOmx,
+	},
+}
+
+func TestNewTickRecordBatch(t *testing.T) {
+	mem := memory.NewCheckedAllocator(memory.NewGoAllocator())
+	defer mem.AssertSize(t, 0)
+
+	batch := NewTickRecordBatch(mem, "GOOG", &activetick.TickDataResponse{Records: ticks})
+	defer batch.Release()
+
+	if !batch.Schema().Equal(TickSchema) || batch.NumRows() != int64(len(ticks)) {
+		t.Fatalf("Unexpected record batch: %v", batch)
+	}
+
+	times := batch.Column(2).(*array.Timestamp)
+	if times.Value(0) != arrow.Timestamp(start.UnixNano()/1e6) {
+		t.Errorf("Unexpected time: %v", times.Value(0))
+	}
+
+	lastPrices := batch.Column(3).(*array.Float64)
+	if lastPrices.Value(0) != 616.55 || !lastPrices.IsNull(1) {
+		t.Errorf("Unexpected last prices: %v", lastPrices)
+	}
+
+	bidExchanges := batch.Column(11).(*array.String)
+	if !bidExchanges.IsNull(0) || bidExchanges.Value(1) != "Q" {
+		t.Errorf("Unexpected bid exchanges: %v", bidExchanges)
+	}
+
+	conditions := batch.Column(6).(*array.FixedSizeList).ListValues().(*array.Int32)
+	if conditions.Len() != 4*len(ticks) || conditions.Value(1) != 14 {
+		t.Errorf("Unexpected conditions: %v", conditions)
+	}
+}
+
+func TestNewBarRecordBatch(t *testing.T) {
+	mem := memory.NewCheckedAllocator(memory.NewGoAllocator())
+	defer mem.AssertSize(t, 0)
+
+	resp := &activetick.BarDataResponse{
+		Records: []*activetick.BarDataRecord{
+			{Time: start, Open: 26.88, High: 26.9, Low: 26.86, Close: 26.89, Volume: 1175094},
+		},
+	}
+
+	batch := NewBarRecordBatch(mem, "SPY", resp)
+	defer batch.Release()
+
+	if batch.NumRows() != 1 || batch.Column(6).(*array.Int64).Value(0) != 1175094 {
+		t.Errorf("Unexpected record batch: %v", batch)
+	}
+}
+
+type sliceIterator struct {
+	records []*activetick.TickRecord
+	i       int
+}
+
+func (it *sliceIterator) Next() bool                     { it.i++; return it.i <= len(it.records) }
+func (it *sliceIterator) Record() *activetick.TickRecord { return it.records[it.i-1] }
+func (it *sliceIterator) Err() error                     { return nil }
+func (it *sliceIterator) Close() error                   { return nil }
+
+func TestReadTickBatches(t *testing.T) {
+	mem := memory.NewCheckedAllocator(memory.NewGoAllocator())
+	defer mem.AssertSize(t, 0)
+
+	var sizes []int64
+	err := ReadTickBatches(mem, "GOOG", &sliceIterator{records: ticks}, 2, func(batch arrow.RecordBatch) error {
+		sizes = append(sizes, batch.NumRows())
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(sizes) != 2 || sizes[0] != 2 || sizes[1] != 1 {
+		t.Errorf("Unexpected batch sizes: %v", sizes)
+	}
+}
+
+func TestReadTickBatchesInvalidSize(t *testing.T) {
+	mem := memory.NewCheckedAllocator(memory.NewGoAllocator())
+	defer mem.AssertSize(t, 0)
+
+	for _, size := range []int{0, -1} {
+		err := ReadTickBatches(mem, "GOOG", &sliceIterator{records: ticks}, size, func(batch arrow.RecordBatch) error {
+			t.Errorf("Unexpected batch of %d rows", batch.NumRows())
+			return nil
+		})
+		if err == nil {
+			t.Errorf("Expected error for batch size %d", size)
+		}
+	}
+}
@@ -0,0 +1,272 @@
+/*
+Package atbars builds OHLCV bars locally from ActiveTick trades.
+
+Bars can be built over fixed time intervals (including sub-minute
+intervals), or every N trades, N shares of volume, or N dollars of traded
+value. Quotes are ignored.
+
+	agg := atbars.NewTimeAggregator(5*time.Second, atbars.Options{})
+	bars := atbars.Aggregate(agg, resp.Records)
+*/
+package atbars
+
+import (
+	"time"
+
+	"github.com/timpalpant/go-activetick"
+)
+
+// Options controls which trades are included in bars and how
+// time-based bars are aligned.
+type Options struct {
+	// ExcludeConditions lists trade conditions that exclude a trade
+	// from bars if any of its conditions is in the list. Excluding
+	// TradeConditionRegular only excludes trades whose first
+	// condition is Regular, since unused slots are also zero.
+	ExcludeConditions []activetick.TradeCondition
+	// Filter, if set, must return true for a trade to be included.
+	// It may be used, for example, to restrict bars to a custom session.
+	Filter func(record *activetick.TickRecord) bool
+	// ConsolidatedRules applies the CTA/UTP eligibility rules of each
+	// trade's conditions: only trades that update the consolidated last
+	// price set Open and Close, only those that update high/low set High
+	// and Low, and only those included in consolidated volume count
+	// towards Volume and the trade, volume and dollar thresholds.
+	//
+	// A bar without trades that update the last price opens and closes
+	// at the previous bar's close, and its High and Low include that
+	// price. Such a bar is dropped if there is no previous bar.
+	ConsolidatedRules bool
+	// Origin aligns time bars, which begin at Origin plus a multiple of
+	// the interval. The zero value aligns bars to midnight UTC.
+	Origin time.Time
+}
+
+type barKind int
+
+const (
+	timeBars barKind = iota
+	tradeBars
+	volumeBars
+	dollarBars
+)
+
+// Aggregator accumulates trades into bars. Trades must be added in time
+// order. An Aggregator is not safe for concurrent use.
+type Aggregator struct {
+	kind      barKind
+	interval  time.Duration
+	threshold float64
+	opts      Options
+
+	bar     *activetick.BarDataRecord
+	hasLast bool
+	hasHL   bool
+	trades  int
+	dollars float64
+	// Close of the previous bar, if any.
+	close    float64
+	hasClose bool
+}
+
+// NewTimeAggregator returns an Aggregator that builds a bar for each
+// interval in which there were trades. Each bar's Time is the start
+// of its interval.
+func NewTimeAggregator(interval time.Duration, opts Options) *Aggregator {
+	return &Aggregator{kind: timeBars, interval: interval, opts: opts}
+}
+
+// NewTradeCountAggregator returns an Aggregator that builds a bar
+// from every n trades. Each bar's Time is that of its first trade.
+func NewTradeCountAggregator(n int, opts Options) *Aggregator {
+	return &Aggregator{kind: tradeBars, threshold: float64(n), opts: opts}
+}
+
+// NewVolumeAggregator returns an Aggregator that closes a bar once its
+// volume reaches volume shares. Trades are not split between bars, so a
+// bar may exceed the threshold. Each bar's Time is that of its first trade.
+func NewVolumeAggregator(volume int64, opts Options) *Aggregator {
+	return &Aggregator{kind: volumeBars, threshold: float64(volume), opts: opts}
+}
+
+// NewDollarAggregator returns an Aggregator that closes a bar once the
+// traded value (price times size) reaches dollars. Trades are not split
+// between bars, so a bar may exceed the threshold. Each bar's Time is
+// that of its first trade.
+func NewDollarAggregator(dollars float64, opts Options) *Aggregator {
+	return &Aggregator{kind: dollarBars, threshold: dollars, opts: opts}
+}
+
+func (a *Aggregator) include(record *activetick.TickRecord) bool {
+	if record.Type != activetick.TickTypeTrade {
+		return false
+	}
+
+	for i, cond := range record.Condition {
+		// Slots after the first that hold the zero value are unused,
+		// rather than additional Regular conditions.
+		if i > 0 && cond == activetick.TradeConditionRegular {
+			continue
+		}
+
+		for _, excluded := range a.opts.ExcludeConditions {
+			if cond == excluded {
+				return false
+			}
+		}
+	}
+
+	return a.opts.Filter == nil || a.opts.Filter(record)
+}
+
+func (a *Aggregator) barTime(t time.Time) time.Time {
+	if a.kind != timeBars {
+		return t
+	}
+
+	// Truncate aligns to the zero time, so shift by the phase of the
+	// origin relative to it.
+	phase := a.opts.Origin.Sub(a.opts.Origin.Truncate(a.interval))
+	return t.Add(-phase).Truncate(a.interval).Add(phase)
+}
+
+// Add adds a tick to the current bar. If the tick completes a bar,
+// the completed bar is returned, otherwise nil. Ticks that are not
+// eligible trades are ignored.
+func (a *Aggregator) Add(record *activetick.TickRecord) *activetick.BarDataRecord {
+	if !a.include(record) {
+		return nil
+	}
+
+	updatesLast, updatesHL, updatesVolume := true, true, true
+	if a.opts.ConsolidatedRules {
+		updatesLast = record.Condition.UpdatesLast()
+		updatesHL = record.Condition.UpdatesHighLow()
+		updatesVolume = record.Condition.UpdatesVolume()
+	}
+
+	if !updatesLast && !updatesHL && !updatesVolume {
+		return nil
+	}
+
+	var done *activetick.BarDataRecord
+	if a.kind == timeBars && a.bar != nil && !a.barTime(record.Time).Equal(a.bar.Time) {
+		done = a.Flush()
+	}
+
+	if a.bar == nil {
+		a.bar = &activetick.BarDataRecord{Time: a.barTime(record.Time)}
+	}
+
+	bar := a.bar
+	price := record.LastPrice
+	if updatesLast {
+		if !a.hasLast {
+			bar.Open = price
+			a.hasLast = true
+		}
+		bar.Close = price
+	}
+	if updatesHL {
+		if !a.hasHL || price > bar.High {
+			bar.High = price
+		}
+		if !a.hasHL || price < bar.Low {
+			bar.Low = price
+		}
+		a.hasHL = true
+	}
+	if !updatesVolume {
+		return done
+	}
+	bar.Volume += record.LastSize
+	a.trades++
+	a.dollars += price * float64(record.LastSize)
+
+	var progress float64
+	switch a.kind {
+	case tradeBars:
+		progress = float64(a.trades)
+	case volumeBars:
+		progress = float64(bar.Volume)
+	case dollarBars:
+		progress = a.dollars
+	default:
+		return done
+	}
+
+	if progress >= a.threshold {
+		return a.Flush()
+	}
+
+	return done
+}
+
+// Flush returns the current, possibly incomplete, bar and starts a new
+// one. It returns nil if no trades have been added since the last bar,
+// or if the bar has no price (see Options.ConsolidatedRules).
+func (a *Aggregator) Flush() *activetick.BarDataRecord {
+	bar := a.bar
+	if bar != nil && !a.hasLast {
+		if a.hasClose {
+			bar.Open, bar.Close = a.close, a.close
+		} else {
+			bar = nil
+		}
+	}
+
+	if bar != nil {
+		if !a.hasHL {
+			bar.High, bar.Low = bar.Open, bar.Open
+		}
+		bar.High = max(bar.High, bar.Open, bar.Close)
+		bar.Low = min(bar.Low, bar.Open, bar.Close)
+		a.close, a.hasClose = bar.Close, true
+	}
+
+	a.bar = nil
+	a.hasLast = false
+	a.hasHL = false
+	a.trades = 0
+	a.dollars = 0
+	return bar
+}
+
+// Aggregate builds bars from records, including the final
+// incomplete bar.
+func Aggregate(a *Aggregator, records []*activetick.TickRecord) []*activetick.BarDataRecord {
+	var bars []*activetick.BarDataRecord
+	for _, record := range records {
+		if bar := a.Add(record); bar != nil {
+			bars = append(bars, bar)
+		}
+	}
+
+	if bar := a.Flush(); bar != nil {
+		bars = append(bars, bar)
+	}
+
+	return bars
+}
+
+// AggregateIterator builds bars from the records of it, passing each bar
+// to fn as it is completed, followed by the final incomplete bar.
+func AggregateIterator(a *Aggregator, it activetick.TickIterator, fn func(*activetick.BarDataRecord) error) error {
+	for it.Next() {
+		if bar := a.Add(it.Record()); bar != nil {
+			if err := fn(bar); err != nil {
+				return err
+			}
+		}
+	}
+
+	if err := it.Err(); err != nil {
+		return err
+	}
+
+	if bar := a.Flush(); bar != nil {
+		return fn(bar)
+	}
+
+	return nil
+}
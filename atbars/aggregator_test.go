@@ -0,0 +1,144 @@
+package atbars
+
+import (
+	"testing"
+	"time"
+
+	"github.com/timpalpant/go-activetick"
+)
+
+var start = time.Date(2016, 10, 4, 13, 30, 0, 0, time.UTC)
+
+func trade(offset time.Duration, price float64, size int64, conds ...activetick.TradeCondition) *activetick.TickRecord {
+	record := &activetick.TickRecord{
+		Type:      activetick.TickTypeTrade,
+		Time:      start.Add(offset),
+		LastPrice: price,
+		LastSize:  size,
+	}
+	copy(record.Condition[:], conds)
+	return record
+}
+
+var ticks = []*activetick.TickRecord{
+	trade(0, 10, 100),
+	{Type: activetick.TickTypeQuote, Time: start, BidPrice: 9.9, AskPrice: 10.1},
+	trade(200*time.Millisecond, 11, 200),
+	trade(900*time.Millisecond, 9, 100),
+	trade(1500*time.Millisecond, 50, 1, activetick.TradeConditionFormT),
+	trade(1600*time.Millisecond, 10.5, 300),
+	trade(3100*time.Millisecond, 10, 400),
+}
+
+func TestTimeAggregator(t *testing.T) {
+	opts := Options{ExcludeConditions: []activetick.TradeCondition{activetick.TradeConditionFormT}}
+	bars := Aggregate(NewTimeAggregator(time.Second, opts), ticks)
+	expected := []activetick.BarDataRecord{
+		{Time: start, Open: 10, High: 11, Low: 9, Close: 9, Volume: 400},
+		{Time: start.Add(time.Second), Open: 10.5, High: 10.5, Low: 10.5, Close: 10.5, Volume: 300},
+		{Time: start.Add(3 * time.Second), Open: 10, High: 10, Low: 10, Close: 10, Volume: 400},
+	}
+
+	if len(bars) != len(expected) {
+		t.Fatalf("Expected %d bars, got %d", len(expected), len(bars))
+	}
+
+	for i, bar := range bars {
+		if *bar != expected[i] {
+			t.Errorf("Bar %d: expected %+v, got %+v", i, expected[i], *bar)
+		}
+	}
+}
+
+func TestTimeAggregatorOrigin(t *testing.T) {
+	opts := Options{Origin: start.Add(500 * time.Millisecond)}
+	bars := Aggregate(NewTimeAggregator(time.Second, opts), ticks[:4])
+	if len(bars) != 2 || !bars[0].Time.Equal(start.Add(-500*time.Millisecond)) {
+		t.Errorf("Unexpected bars: %+v", bars)
+	}
+}
+
+func TestThresholdAggregators(t *testing.T) {
+	tests := []struct {
+		name    string
+		agg     *Aggregator
+		volumes []int64
+	}{
+		{"trades", NewTradeCountAggregator(2, Options{}), []int64{300, 101, 700}},
+		{"volume", NewVolumeAggregator(300, Options{}), []int64{300, 401, 400}},
+		{"dollars", NewDollarAggregator(3000, Options{}), []int64{300, 401, 400}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bars := Aggregate(tt.agg, ticks)
+			if len(bars) != len(tt.volumes) {
+				t.Fatalf("Expected %d bars, got %d", len(tt.volumes), len(bars))
+			}
+
+			for i, bar := range bars {
+				if bar.Volume != tt.volumes[i] {
+					t.Errorf("Bar %d: expected volume %d, got %d", i, tt.volumes[i], bar.Volume)
+				}
+			}
+		})
+	}
+}
+
+func TestConsolidatedRules(t *testing.T) {
+	ticks := []*activetick.TickRecord{
+		trade(0, 10, 100, activetick.TradeConditionFormT),
+		trade(100*time.Millisecond, 11, 100),
+		trade(200*time.Millisecond, 12, 100, activetick.TradeConditionSoldOutOfSequence),
+		trade(300*time.Millisecond, 10.5, 100),
+		trade(400*time.Millisecond, 20, 100, activetick.TradeConditionAveragePrice),
+		trade(500*time.Millisecond, 10.8, 0, activetick.TradeConditionMarketCenterOfficialClose),
+	}
+
+	bars := Aggregate(NewTimeAggregator(time.Second, Options{ConsolidatedRules: true}), ticks)
+	expected := activetick.BarDataRecord{Time: start, Open: 11, High: 12, Low: 10.5, Close: 10.5, Volume: 500}
+	if len(bars) != 1 || *bars[0] != expected {
+		t.Errorf("Expected %+v, got %+v", expected, bars)
+	}
+}
+
+func TestConsolidatedRulesCarryClose(t *testing.T) {
+	ticks := []*activetick.TickRecord{
+		trade(0, 9, 100, activetick.TradeConditionFormT),
+		trade(time.Second, 10, 100),
+		trade(2*time.Second, 50, 200, activetick.TradeConditionFormT),
+		trade(3*time.Second, 12, 100, activetick.TradeConditionSoldOutOfSequence),
+	}
+
+	bars := Aggregate(NewTimeAggregator(time.Second, Options{ConsolidatedRules: true}), ticks)
+	expected := []activetick.BarDataRecord{
+		{Time: start.Add(time.Second), Open: 10, High: 10, Low: 10, Close: 10, Volume: 100},
+		{Time: start.Add(2 * time.Second), Open: 10, High: 10, Low: 10, Close: 10, Volume: 200},
+		{Time: start.Add(3 * time.Second), Open: 10, High: 12, Low: 10, Close: 10, Volume: 100},
+	}
+
+	if len(bars) != len(expected) {
+		t.Fatalf("Expected %d bars, got %d", len(expected), len(bars))
+	}
+
+	for i, bar := range bars {
+		if *bar != expected[i] {
+			t.Errorf("Bar %d: expected %+v, got %+v", i, expected[i], *bar)
+		}
+	}
+}
+
+func TestExcludeRegular(t *testing.T) {
+	ticks := []*activetick.TickRecord{
+		trade(0, 10, 100),
+		trade(100*time.Millisecond, 11, 200, activetick.TradeConditionInterMarketSweep),
+		trade(200*time.Millisecond, 12, 300, activetick.TradeConditionRegular, activetick.TradeConditionFormT),
+	}
+
+	opts := Options{ExcludeConditions: []activetick.TradeCondition{activetick.TradeConditionRegular}}
+	bars := Aggregate(NewTimeAggregator(time.Second, opts), ticks)
+	expected := activetick.BarDataRecord{Time: start, Open: 11, High: 11, Low: 11, Close: 11, Volume: 200}
+	if len(bars) != 1 || *bars[0] != expected {
+		t.Errorf("Expected %+v, got %+v", expected, bars)
+	}
+}
@@ -0,0 +1,312 @@
+package main
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/timpalpant/go-activetick"
+	"github.com/timpalpant/go-activetick/atparquet"
+	"github.com/timpalpant/go-activetick/calendar"
+)
+
+var (
+	barHeader = []string{"symbol", "time", "open", "high", "low", "close", "volume"}
+
+	tickHeader = []string{
+		"symbol", "type", "time",
+		"last_price", "last_size", "last_exchange",
+		"condition1", "condition2", "condition3", "condition4",
+		"bid_price", "ask_price", "bid_size", "ask_size",
+		"bid_exchange", "ask_exchange",
+	}
+)
+
+// recordWriter writes bars and ticks in one of the output formats.
+type recordWriter interface {
+	WriteBar(symbol string, record *activetick.BarDataRecord) error
+	WriteTick(symbol string, record *activetick.TickRecord) error
+	Flush() error
+}
+
+// timeFormatter renders a time for output: as a string for text formats,
+// or possibly as a number for JSON.
+type timeFormatter func(t time.Time) interface{}
+
+func newTimeFormatter(name string) (timeFormatter, error) {
+	switch name {
+	case "rfc3339":
+		return func(t time.Time) interface{} {
+			return t.UTC().Format(time.RFC3339Nano)
+		}, nil
+	case "epoch_ms":
+		return func(t time.Time) interface{} {
+			return t.UnixNano() / int64(time.Millisecond)
+		}, nil
+	case "local":
+		return func(t time.Time) interface{} {
+			return t.In(calendar.Location).Format(time.RFC3339Nano)
+		}, nil
+	default:
+		return nil, fmt.Errorf("Invalid time format: %v", name)
+	}
+}
+
+// newRecordWriter returns a writer for records of dataType (bar or tick)
+// in the given output format.
+func newRecordWriter(format, dataType string, w io.Writer, formatTime timeFormatter) (recordWriter, error) {
+	switch format {
+	case "csv":
+		return &csvWriter{w: csv.NewWriter(w), formatTime: formatTime}, nil
+	case "json":
+		return &jsonWriter{w: bufio.NewWriter(w), formatTime: formatTime, array: true}, nil
+	case "ndjson":
+		return &jsonWriter{w: bufio.NewWriter(w), formatTime: formatTime}, nil
+	case "parquet":
+		return &parquetWriter{w: w, dataType: dataType}, nil
+	default:
+		return nil, fmt.Errorf("Invalid output format: %v", format)
+	}
+}
+
+// csvWriter writes a header followed by one row per record. Trades and
+// quotes share the same columns, with the fields of the other type empty.
+type csvWriter struct {
+	w             *csv.Writer
+	formatTime    timeFormatter
+	headerWritten bool
+}
+
+func (cw *csvWriter) writeHeader(header []string) error {
+	if cw.headerWritten {
+		return nil
+	}
+
+	cw.headerWritten = true
+	return cw.w.Write(header)
+}
+
+func (cw *csvWriter) WriteBar(symbol string, record *activetick.BarDataRecord) error {
+	if err := cw.writeHeader(barHeader); err != nil {
+		return err
+	}
+
+	return cw.w.Write([]string{
+		symbol,
+		fmt.Sprint(cw.formatTime(record.Time)),
+		formatFloat(record.Open),
+		formatFloat(record.High),
+		formatFloat(record.Low),
+		formatFloat(record.Close),
+		strconv.FormatInt(record.Volume, 10),
+	})
+}
+
+func (cw *csvWriter) WriteTick(symbol string, record *activetick.TickRecord) error {
+	if err := cw.writeHeader(tickHeader); err != nil {
+		return err
+	}
+
+	row := make([]string, len(tickHeader))
+	row[0] = symbol
+	row[1] = string(record.Type)
+	row[2] = fmt.Sprint(cw.formatTime(record.Time))
+	switch record.Type {
+	case activetick.TickTypeTrade:
+		row[3] = formatFloat(record.LastPrice)
+		row[4] = strconv.FormatInt(record.LastSize, 10)
+		row[5] = string(record.LastExchange)
+		for i, cond := range record.Condition {
+			row[6+i] = strconv.Itoa(int(cond))
+		}
+	case activetick.TickTypeQuote:
+		row[6] = strconv.Itoa(int(record.Condition[0]))
+		row[10] = formatFloat(record.BidPrice)
+		row[11] = formatFloat(record.AskPrice)
+		row[12] = strconv.FormatInt(record.BidSize, 10)
+		row[13] = strconv.FormatInt(record.AskSize, 10)
+		row[14] = string(record.BidExchange)
+		row[15] = string(record.AskExchange)
+	}
+
+	return cw.w.Write(row)
+}
+
+func (cw *csvWriter) Flush() error {
+	cw.w.Flush()
+	return cw.w.Error()
+}
+
+type jsonBar struct {
+	Symbol string      `json:"symbol"`
+	Time   interface{} `json:"time"`
+	Open   float64     `json:"open"`
+	High   float64     `json:"high"`
+	Low    float64     `json:"low"`
+	Close  float64     `json:"close"`
+	Volume int64       `json:"volume"`
+}
+
+type jsonTick struct {
+	Symbol       string      `json:"symbol"`
+	Type         string      `json:"type"`
+	Time         interface{} `json:"time"`
+	LastPrice    float64     `json:"last_price,omitempty"`
+	LastSize     int64       `json:"last_size,omitempty"`
+	LastExchange string      `json:"last_exchange,omitempty"`
+	Conditions   []int       `json:"conditions"`
+	BidPrice     float64     `json:"bid_price,omitempty"`
+	AskPrice     float64     `json:"ask_price,omitempty"`
+	BidSize      int64       `json:"bid_size,omitempty"`
+	AskSize      int64       `json:"ask_size,omitempty"`
+	BidExchange  string      `json:"bid_exchange,omitempty"`
+	AskExchange  string      `json:"ask_exchange,omitempty"`
+}
+
+// jsonWriter writes records either as a single JSON array,
+// or as newline-delimited JSON objects.
+type jsonWriter struct {
+	w          *bufio.Writer
+	formatTime timeFormatter
+	array      bool
+	n          int
+}
+
+func (jw *jsonWriter) write(v interface{}) error {
+	buf, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+
+	if jw.array {
+		sep := ",\n"
+		if jw.n == 0 {
+			sep = "[\n"
+		}
+
+		if _, err := jw.w.WriteString(sep); err != nil {
+			return err
+		}
+	}
+
+	jw.n++
+	if _, err := jw.w.Write(buf); err != nil {
+		return err
+	}
+
+	if !jw.array {
+		return jw.w.WriteByte('\n')
+	}
+
+	return nil
+}
+
+func (jw *jsonWriter) WriteBar(symbol string, record *activetick.BarDataRecord) error {
+	return jw.write(&jsonBar{
+		Symbol: symbol,
+		Time:   jw.formatTime(record.Time),
+		Open:   record.Open,
+		High:   record.High,
+		Low:    record.Low,
+		Close:  record.Close,
+		Volume: record.Volume,
+	})
+}
+
+func (jw *jsonWriter) WriteTick(symbol string, record *activetick.TickRecord) error {
+	tick := &jsonTick{
+		Symbol: symbol,
+		Type:   string(record.Type),
+		Time:   jw.formatTime(record.Time),
+	}
+
+	switch record.Type {
+	case activetick.TickTypeTrade:
+		tick.LastPrice = record.LastPrice
+		tick.LastSize = record.LastSize
+		tick.LastExchange = string(record.LastExchange)
+		for _, cond := range record.Condition {
+			tick.Conditions = append(tick.Conditions, int(cond))
+		}
+	case activetick.TickTypeQuote:
+		tick.Conditions = []int{int(record.Condition[0])}
+		tick.BidPrice = record.BidPrice
+		tick.AskPrice = record.AskPrice
+		tick.BidSize = record.BidSize
+		tick.AskSize = record.AskSize
+		tick.BidExchange = string(record.BidExchange)
+		tick.AskExchange = string(record.AskExchange)
+	}
+
+	return jw.write(tick)
+}
+
+func (jw *jsonWriter) Flush() error {
+	if jw.array {
+		end := "\n]\n"
+		if jw.n == 0 {
+			end = "[]\n"
+		}
+
+		if _, err := jw.w.WriteString(end); err != nil {
+			return err
+		}
+	}
+
+	return jw.w.Flush()
+}
+
+// parquetWriter writes a Parquet file of either bars or ticks, depending
+// on the first record written. If no records are written, an empty file
+// with the schema of dataType is written. Times are always stored as
+// timestamps.
+type parquetWriter struct {
+	w        io.Writer
+	dataType string
+	bars     *atparquet.BarWriter
+	ticks    *atparquet.TickWriter
+}
+
+func (pw *parquetWriter) WriteBar(symbol string, record *activetick.BarDataRecord) error {
+	if pw.ticks != nil {
+		return fmt.Errorf("Cannot write bars and ticks to the same Parquet file")
+	}
+
+	if pw.bars == nil {
+		pw.bars = atparquet.NewBarWriter(pw.w)
+	}
+
+	return pw.bars.Write(symbol, record)
+}
+
+func (pw *parquetWriter) WriteTick(symbol string, record *activetick.TickRecord) error {
+	if pw.bars != nil {
+		return fmt.Errorf("Cannot write bars and ticks to the same Parquet file")
+	}
+
+	if pw.ticks == nil {
+		pw.ticks = atparquet.NewTickWriter(pw.w)
+	}
+
+	return pw.ticks.Write(symbol, record)
+}
+
+func (pw *parquetWriter) Flush() error {
+	if pw.bars == nil && pw.ticks == nil {
+		if pw.dataType == "tick" {
+			pw.ticks = atparquet.NewTickWriter(pw.w)
+		} else {
+			pw.bars = atparquet.NewBarWriter(pw.w)
+		}
+	}
+
+	if pw.bars != nil {
+		return pw.bars.Close()
+	}
+
+	return pw.ticks.Close()
+}
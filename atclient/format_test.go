@@ -0,0 +1,118 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/parquet-go/parquet-go"
+	"github.com/timpalpant/go-activetick"
+	"github.com/timpalpant/go-activetick/atparquet"
+)
+
+var formatTicks = []*activetick.TickRecord{
+	{
+		Type:         activetick.TickTypeTrade,
+		Time:         time.Date(2012, 8, 3, 19, 30, 0, 551e6, time.UTC),
+		LastPrice:    616.55,
+		LastSize:     100,
+		LastExchange: activetick.ExchangeBatsYExchange,
+		Condition:    [4]activetick.TradeCondition{0, 14, 0, 0},
+	},
+	{
+		Type:        activetick.TickTypeQuote,
+		Time:        time.Date(2012, 8, 3, 19, 30, 0, 601e6, time.UTC),
+		BidPrice:    616.5,
+		AskPrice:    616.6,
+		BidSize:     200,
+		AskSize:     300,
+		BidExchange: activetick.ExchangeNasdaqOmx,
+		AskExchange: activetick.ExchangeNyseArcaExchange,
+	},
+}
+
+func writeTicks(t *testing.T, format, timeFormat string) string {
+	formatTime, err := newTimeFormatter(timeFormat)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	w, err := newRecordWriter(format, "tick", &buf, formatTime)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tick := range formatTicks {
+		if err := w.WriteTick("GOOG", tick); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := w.Flush(); err != nil {
+		t.Fatal(err)
+	}
+
+	return buf.String()
+}
+
+func TestCSVFormat(t *testing.T) {
+	lines := strings.Split(strings.TrimSpace(writeTicks(t, "csv", "local")), "\n")
+	expected := []string{
+		strings.Join(tickHeader, ","),
+		"GOOG,T,2012-08-03T15:30:00.551-04:00,616.55,100,Y,0,14,0,0,,,,,,",
+		"GOOG,Q,2012-08-03T15:30:00.601-04:00,,,,0,,,,616.5,616.6,200,300,Q,P",
+	}
+
+	if len(lines) != len(expected) {
+		t.Fatalf("Expected %d lines, got %d: %v", len(expected), len(lines), lines)
+	}
+
+	for i := range lines {
+		if lines[i] != expected[i] {
+			t.Errorf("Line %d: expected %q, got %q", i, expected[i], lines[i])
+		}
+	}
+}
+
+func TestJSONFormats(t *testing.T) {
+	var ticks []map[string]interface{}
+	if err := json.Unmarshal([]byte(writeTicks(t, "json", "epoch_ms")), &ticks); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(ticks) != 2 || ticks[0]["time"] != float64(1344022200551) {
+		t.Errorf("Unexpected JSON output: %v", ticks)
+	}
+
+	lines := strings.Split(strings.TrimSpace(writeTicks(t, "ndjson", "rfc3339")), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("Expected %d lines, got %d", 2, len(lines))
+	}
+
+	for _, line := range lines {
+		var tick map[string]interface{}
+		if err := json.Unmarshal([]byte(line), &tick); err != nil {
+			t.Error(err)
+		}
+	}
+}
+
+func TestEmptyParquet(t *testing.T) {
+	var buf bytes.Buffer
+	w, err := newRecordWriter("parquet", "tick", &buf, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := w.Flush(); err != nil {
+		t.Fatal(err)
+	}
+
+	rows, err := parquet.Read[atparquet.Tick](bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil || len(rows) != 0 {
+		t.Errorf("Expected empty Parquet file, got %d rows: %v", len(rows), err)
+	}
+}
@@ -1,70 +1,144 @@
 package main
 
 import (
+	"context"
 	"flag"
 	"fmt"
 	"log"
 	"net/http"
+	"os"
+	"strings"
 	"time"
 
 	"github.com/timpalpant/go-activetick"
+	"github.com/timpalpant/go-activetick/calendar"
 )
 
-func fetchBarData(client *activetick.PagingClient, symbol string, start, end time.Time) {
-	req := &activetick.BarDataRequest{
-		Symbol:          symbol,
-		HistoryType:     activetick.HistoryTypeIntraday,
-		IntradayMinutes: 1,
-		BeginTime:       start,
-		EndTime:         end,
-	}
+// window is a time range requested from the server.
+type window struct {
+	begin, end time.Time
+}
 
-	resp, err := client.GetBarData(req)
-	if err != nil {
-		log.Fatal(err)
-	}
+// sessionWindows returns the part of [start, end] within session
+// on each trading day.
+func sessionWindows(session calendar.Session, start, end time.Time) []window {
+	var windows []window
+	for _, day := range calendar.TradingDays(start, end) {
+		begin, stop := day.Session(session)
+		if begin.Before(start) {
+			begin = start
+		}
+		if stop.After(end) {
+			stop = end
+		}
 
-	for _, record := range resp.Records {
-		fmt.Printf("%v,%v,%v,%v,%v,%v\n", record.Time, record.Open,
-			record.High, record.Low, record.Close, record.Volume)
+		if begin.Before(stop) {
+			windows = append(windows, window{begin, stop})
+		}
 	}
+
+	return windows
 }
 
-func fetchTickData(client *activetick.PagingClient, symbol string, start, end time.Time) {
-	req := &activetick.TickDataRequest{
-		Symbol:    symbol,
-		BeginTime: start,
-		EndTime:   end,
-		Trades:    true,
-		Quotes:    true,
-	}
+// fetchBarData writes the bars of each window as it is fetched, so that
+// output is ordered by window and then by symbol.
+func fetchBarData(client *activetick.BatchClient, w recordWriter, symbols []string, windows []window, keep func(calendar.Session) bool) {
+	for _, window := range windows {
+		req := &activetick.BarDataRequest{
+			HistoryType:     activetick.HistoryTypeIntraday,
+			IntradayMinutes: 1,
+			BeginTime:       window.begin,
+			EndTime:         window.end,
+		}
 
-	resp, err := client.GetTickData(req)
-	if err != nil {
-		log.Fatal(err)
+		results := client.GetBarData(context.Background(), symbols, req)
+		for _, result := range results {
+			if result.Err != nil {
+				log.Fatalf("%v: %v", result.Symbol, result.Err)
+			}
+
+			for _, record := range result.Response.Records {
+				if !keep(record.Session) {
+					continue
+				}
+
+				if err := w.WriteBar(result.Symbol, record); err != nil {
+					log.Fatal(err)
+				}
+			}
+		}
 	}
+}
 
-	for _, record := range resp.Records {
-		if record.Type == activetick.TickTypeQuote {
-			fmt.Printf("%v,%v,%v,%v,%v,%v,%v\n", record.Time,
-				record.BidPrice, record.BidSize, record.BidExchange,
-				record.AskPrice, record.AskSize, record.AskExchange)
-		} else {
-			fmt.Printf("%v,%v,%v,%v\n", record.Time,
-				record.LastPrice, record.LastSize, record.LastExchange)
+// fetchTickData writes the ticks of each window as it is fetched, so that
+// output is ordered by window and then by symbol.
+func fetchTickData(client *activetick.BatchClient, w recordWriter, symbols []string, windows []window, keep func(calendar.Session) bool) {
+	for _, window := range windows {
+		req := &activetick.TickDataRequest{
+			BeginTime: window.begin,
+			EndTime:   window.end,
+			Trades:    true,
+			Quotes:    true,
+		}
+
+		results := client.GetTickData(context.Background(), symbols, req)
+		for _, result := range results {
+			if result.Err != nil {
+				log.Fatalf("%v: %v", result.Symbol, result.Err)
+			}
+
+			for _, record := range result.Response.Records {
+				if !keep(record.Session) {
+					continue
+				}
+
+				if err := w.WriteTick(result.Symbol, record); err != nil {
+					log.Fatal(err)
+				}
+			}
 		}
 	}
 }
 
 func main() {
+	if len(os.Args) > 1 && os.Args[1] == "sync" {
+		runSync(os.Args[2:])
+		return
+	}
+
 	host := flag.String("host", "localhost", "ActiveTick HTTP server host")
 	port := flag.Int("port", 5000, "ActiveTick HTTP port")
-	symbol := flag.String("symbol", "SPY", "Symbol to fetch data for")
+	symbol := flag.String("symbol", "SPY", "Comma-separated symbols to fetch data for")
+	parallelism := flag.Int("parallelism", 4, "Number of symbols to fetch concurrently")
 	dataType := flag.String("type", "bar", "Type of data to fetch (tick/bar)")
-	beginTime := flag.String("begin_time", "2016-10-04T14:30:00Z", "Earliest time to fetch (RFC3339)")
-	endTime := flag.String("end_time", "2016-10-04T14:40:00Z", "Latest time to fetch (RFC3339)")
+	beginTime := flag.String("begin_time", "2016-10-04T09:30:00-04:00", "Earliest time to fetch (RFC3339)")
+	endTime := flag.String("end_time", "2016-10-04T09:40:00-04:00", "Latest time to fetch (RFC3339)")
+	format := flag.String("format", "csv", "Output format (csv/json/ndjson/parquet)")
+	out := flag.String("out", "", "Output file (default stdout)")
+	timeFormat := flag.String("time_format", "rfc3339",
+		"Output time format (rfc3339/epoch_ms/local), where local is US Eastern time")
+	session := flag.String("session", "",
+		"Only fetch data in this session of each trading day (pre-market/regular/after-hours)")
 	flag.Parse()
 
+	formatTime, err := newTimeFormatter(*timeFormat)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	output := os.Stdout
+	if *out != "" {
+		output, err = os.Create(*out)
+		if err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	w, err := newRecordWriter(*format, *dataType, output, formatTime)
+	if err != nil {
+		log.Fatal(err)
+	}
+
 	startDate, err := time.Parse(time.RFC3339, *beginTime)
 	if err != nil {
 		log.Fatal(err)
@@ -74,15 +148,40 @@ func main() {
 		log.Fatal(err)
 	}
 
+	windows := []window{{startDate, endDate}}
+	keep := func(calendar.Session) bool { return true }
+	if *session != "" {
+		var s calendar.Session
+		if err := s.UnmarshalText([]byte(*session)); err != nil || s == calendar.SessionClosed {
+			log.Fatalf("Invalid session: %v", *session)
+		}
+
+		windows = sessionWindows(s, startDate, endDate)
+		keep = func(rs calendar.Session) bool { return rs == s }
+	}
+
+	symbols := strings.Split(*symbol, ",")
 	endpoint := fmt.Sprintf("http://%s:%d", *host, *port)
-	client := activetick.NewPagingClient(activetick.NewClient(&http.Client{}, endpoint))
+	client := activetick.NewBatchClient(
+		activetick.NewPagingClient(activetick.NewClient(&http.Client{}, endpoint)),
+		*parallelism)
 
 	switch *dataType {
 	case "bar":
-		fetchBarData(client, *symbol, startDate, endDate)
+		fetchBarData(client, w, symbols, windows, keep)
 	case "tick":
-		fetchTickData(client, *symbol, startDate, endDate)
+		fetchTickData(client, w, symbols, windows, keep)
 	default:
-		log.Fatal("Invalid data type: %v", *dataType)
+		log.Fatalf("Invalid data type: %v", *dataType)
+	}
+
+	if err := w.Flush(); err != nil {
+		log.Fatal(err)
+	}
+
+	if *out != "" {
+		if err := output.Close(); err != nil {
+			log.Fatal(err)
+		}
 	}
 }
@@ -0,0 +1,31 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/timpalpant/go-activetick/calendar"
+)
+
+func TestSessionWindows(t *testing.T) {
+	at := func(d, hour, min int) time.Time {
+		return time.Date(2024, 11, d, hour, min, 0, 0, calendar.Location)
+	}
+
+	// Thanksgiving is closed and the day after closes early.
+	windows := sessionWindows(calendar.SessionRegular, at(27, 10, 0), at(30, 0, 0))
+	expected := []window{
+		{at(27, 10, 0), at(27, 16, 0)},
+		{at(29, 9, 30), at(29, 13, 0)},
+	}
+
+	if len(windows) != len(expected) {
+		t.Fatalf("Expected %d windows, got %v", len(expected), windows)
+	}
+
+	for i, w := range windows {
+		if !w.begin.Equal(expected[i].begin) || !w.end.Equal(expected[i].end) {
+			t.Errorf("Window %d: expected %v, got %v", i, expected[i], w)
+		}
+	}
+}
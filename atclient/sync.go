@@ -0,0 +1,405 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/timpalpant/go-activetick"
+	"github.com/timpalpant/go-activetick/internal/atomicfile"
+)
+
+const (
+	manifestName  = "manifest.json"
+	dayFileFormat = "20060102"
+)
+
+// manifest records how far each symbol and data type has been synced.
+type manifest struct {
+	Symbols map[string]map[string]*syncState `json:"symbols"`
+}
+
+type syncState struct {
+	// Through is the end of the most recently synced time range.
+	// All data up to and including this time has been stored.
+	Through time.Time `json:"through"`
+	// LastRecord is the time of the latest stored record.
+	LastRecord time.Time `json:"last_record,omitempty"`
+	SyncedAt   time.Time `json:"synced_at"`
+}
+
+func loadManifest(dir string) (*manifest, error) {
+	m := &manifest{Symbols: make(map[string]map[string]*syncState)}
+	buf, err := ioutil.ReadFile(filepath.Join(dir, manifestName))
+	if os.IsNotExist(err) {
+		return m, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(buf, m); err != nil {
+		return nil, err
+	}
+
+	if m.Symbols == nil {
+		m.Symbols = make(map[string]map[string]*syncState)
+	}
+
+	return m, nil
+}
+
+func (m *manifest) save(dir string) error {
+	buf, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return atomicfile.Write(filepath.Join(dir, manifestName), func(w io.Writer) error {
+		_, err := w.Write(buf)
+		return err
+	})
+}
+
+func (m *manifest) state(symbol, dataType string) *syncState {
+	return m.Symbols[symbol][dataType]
+}
+
+func (m *manifest) update(symbol, dataType string, state *syncState) {
+	if m.Symbols[symbol] == nil {
+		m.Symbols[symbol] = make(map[string]*syncState)
+	}
+
+	m.Symbols[symbol][dataType] = state
+}
+
+// dayStore writes the rows of each day to its own CSV file, merging
+// them with any rows already stored for that day.
+type dayStore struct {
+	dir string
+	// Stored rows at or after since are replaced, so that re-syncing
+	// a range after an interrupted sync does not duplicate rows.
+	since time.Time
+
+	day  string
+	rows [][]string
+}
+
+// newDayStore returns a dayStore for the data stored under key,
+// as returned by syncKey.
+func newDayStore(dir, symbol, key string, since time.Time) *dayStore {
+	return &dayStore{
+		dir:   filepath.Join(dir, url.PathEscape(symbol), filepath.FromSlash(key)),
+		since: since,
+	}
+}
+
+// add buffers a row for the day of t, flushing the previous day if needed.
+// Rows must be added in time order, with the time as the first column.
+func (s *dayStore) add(t time.Time, row []string) error {
+	day := t.Format(dayFileFormat)
+	if day != s.day {
+		if err := s.flush(); err != nil {
+			return err
+		}
+
+		s.day = day
+	}
+
+	s.rows = append(s.rows, row)
+	return nil
+}
+
+func (s *dayStore) flush() error {
+	if len(s.rows) == 0 {
+		return nil
+	}
+
+	path := filepath.Join(s.dir, s.day+".csv")
+	existing, err := readRows(path)
+	if err != nil {
+		return err
+	}
+
+	var rows [][]string
+	for _, row := range existing {
+		t, err := time.Parse(time.RFC3339Nano, row[0])
+		if err != nil {
+			return fmt.Errorf("%v: %v", path, err)
+		}
+
+		if t.Before(s.since) {
+			rows = append(rows, row)
+		}
+	}
+	rows = append(rows, s.rows...)
+
+	err = atomicfile.Write(path, func(w io.Writer) error {
+		cw := csv.NewWriter(w)
+		cw.WriteAll(rows)
+		return cw.Error()
+	})
+	s.rows = nil
+	return err
+}
+
+func readRows(path string) ([][]string, error) {
+	f, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	r := csv.NewReader(f)
+	r.FieldsPerRecord = -1
+	return r.ReadAll()
+}
+
+func formatFloat(f float64) string {
+	return strconv.FormatFloat(f, 'f', -1, 64)
+}
+
+func barRow(record *activetick.BarDataRecord) []string {
+	return []string{
+		record.Time.Format(time.RFC3339Nano),
+		formatFloat(record.Open),
+		formatFloat(record.High),
+		formatFloat(record.Low),
+		formatFloat(record.Close),
+		strconv.FormatInt(record.Volume, 10),
+	}
+}
+
+func tickRow(record *activetick.TickRecord) []string {
+	row := []string{
+		record.Time.Format(time.RFC3339Nano),
+		string(record.Type),
+		formatFloat(record.LastPrice),
+		strconv.FormatInt(record.LastSize, 10),
+		string(record.LastExchange),
+	}
+
+	for _, cond := range record.Condition {
+		row = append(row, strconv.Itoa(int(cond)))
+	}
+
+	return append(row,
+		formatFloat(record.BidPrice),
+		formatFloat(record.AskPrice),
+		strconv.FormatInt(record.BidSize, 10),
+		strconv.FormatInt(record.AskSize, 10),
+		string(record.BidExchange),
+		string(record.AskExchange))
+}
+
+func syncBars(ctx context.Context, client *activetick.PagingClient, store *dayStore,
+	symbol string, minutes int, begin, end time.Time) (time.Time, error) {
+	resp, err := client.GetBarDataContext(ctx, &activetick.BarDataRequest{
+		Symbol:          symbol,
+		HistoryType:     activetick.HistoryTypeIntraday,
+		IntradayMinutes: minutes,
+		BeginTime:       begin,
+		EndTime:         end,
+	})
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	var last time.Time
+	for _, record := range resp.Records {
+		if err := store.add(record.Time, barRow(record)); err != nil {
+			return last, err
+		}
+
+		last = record.Time
+	}
+
+	return last, store.flush()
+}
+
+func syncTicks(ctx context.Context, client *activetick.PagingClient, store *dayStore,
+	symbol string, begin, end time.Time) (time.Time, error) {
+	it, err := client.TickIterator(ctx, &activetick.TickDataRequest{
+		Symbol:    symbol,
+		Trades:    true,
+		Quotes:    true,
+		BeginTime: begin,
+		EndTime:   end,
+	})
+	if err != nil {
+		return time.Time{}, err
+	}
+	defer it.Close()
+
+	var last time.Time
+	for it.Next() {
+		record := it.Record()
+		if err := store.add(record.Time, tickRow(record)); err != nil {
+			return last, err
+		}
+
+		last = record.Time
+	}
+
+	if err := it.Err(); err != nil {
+		return last, err
+	}
+
+	return last, store.flush()
+}
+
+func readSymbols(path string) ([]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var symbols []string
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		symbols = append(symbols, line)
+	}
+
+	return symbols, scanner.Err()
+}
+
+// syncKey returns the key of dataType in the manifest, which is also
+// its directory within each symbol's directory. Bars of each size are
+// stored separately.
+func syncKey(dataType string, minutes int) string {
+	if dataType == "bar" {
+		return fmt.Sprintf("bar/%dm", minutes)
+	}
+
+	return dataType
+}
+
+// syncOptions are the settings of a sync run.
+type syncOptions struct {
+	dir       string
+	dataTypes []string
+	minutes   int
+	// since is where symbols that have not been synced start.
+	since time.Time
+	// end is the latest time to sync through.
+	end time.Time
+}
+
+// syncSymbols fetches all data newer than what is recorded in m for each
+// symbol and data type, saving m after each is stored.
+func syncSymbols(ctx context.Context, client *activetick.PagingClient, m *manifest,
+	symbols []string, opts syncOptions) error {
+	for _, symbol := range symbols {
+		for _, dataType := range opts.dataTypes {
+			key := syncKey(dataType, opts.minutes)
+			state := m.state(symbol, key)
+			begin := opts.since
+			if state != nil {
+				begin = state.Through.Add(time.Second)
+			}
+
+			// Only store complete bars, which end by opts.end.
+			end := opts.end
+			if dataType == "bar" {
+				end = end.Truncate(time.Duration(opts.minutes) * time.Minute).Add(-time.Second)
+			}
+
+			if begin.After(end) {
+				continue
+			}
+
+			store := newDayStore(opts.dir, symbol, key, begin)
+			var last time.Time
+			var err error
+			switch dataType {
+			case "bar":
+				last, err = syncBars(ctx, client, store, symbol, opts.minutes, begin, end)
+			case "tick":
+				last, err = syncTicks(ctx, client, store, symbol, begin, end)
+			default:
+				return fmt.Errorf("Invalid data type: %v", dataType)
+			}
+
+			if err != nil {
+				return fmt.Errorf("%v %v: %v", symbol, key, err)
+			}
+
+			newState := &syncState{Through: end, LastRecord: last, SyncedAt: time.Now()}
+			if last.IsZero() && state != nil {
+				newState.LastRecord = state.LastRecord
+			}
+
+			m.update(symbol, key, newState)
+			if err := m.save(opts.dir); err != nil {
+				return err
+			}
+
+			log.Printf("Synced %v %v through %v", symbol, key, end)
+		}
+	}
+
+	return nil
+}
+
+// runSync implements the sync subcommand, which fetches all data newer
+// than what is already stored for each symbol and data type.
+func runSync(args []string) {
+	flags := flag.NewFlagSet("sync", flag.ExitOnError)
+	host := flags.String("host", "localhost", "ActiveTick HTTP server host")
+	port := flags.Int("port", 5000, "ActiveTick HTTP port")
+	symbolsFile := flags.String("symbols", "symbols.txt", "File with one symbol per line")
+	dir := flags.String("dir", "data", "Data directory")
+	dataTypes := flags.String("types", "bar,tick", "Comma-separated types of data to sync (tick/bar)")
+	minutes := flags.Int("minutes", 1, "Bar size in minutes")
+	since := flags.String("since", "2016-10-04T00:00:00Z", "Time to start from for symbols that have not been synced (RFC3339)")
+	delay := flags.Duration("delay", time.Minute, "Only sync data older than this, to avoid storing incomplete seconds")
+	flags.Parse(args)
+
+	sinceTime, err := time.Parse(time.RFC3339, *since)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	symbols, err := readSymbols(*symbolsFile)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	m, err := loadManifest(*dir)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	endpoint := fmt.Sprintf("http://%s:%d", *host, *port)
+	client := activetick.NewPagingClient(activetick.NewClient(&http.Client{}, endpoint))
+	err = syncSymbols(context.Background(), client, m, symbols, syncOptions{
+		dir:       *dir,
+		dataTypes: strings.Split(*dataTypes, ","),
+		minutes:   *minutes,
+		since:     sinceTime,
+		end:       time.Now().Add(-*delay).Truncate(time.Second),
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+}
@@ -0,0 +1,118 @@
+package main
+
+import (
+	"context"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/timpalpant/go-activetick"
+	"github.com/timpalpant/go-activetick/attest"
+	"github.com/timpalpant/go-activetick/calendar"
+)
+
+func TestDayStoreResync(t *testing.T) {
+	dir := t.TempDir()
+	start := time.Date(2016, 10, 4, 14, 30, 0, 0, time.UTC)
+	write := func(since time.Time, n int) {
+		store := newDayStore(dir, "SPY", "bar/1m", since)
+		for i := 0; i < n; i++ {
+			ts := since.Add(time.Duration(i) * time.Minute)
+			if err := store.add(ts, []string{ts.Format(time.RFC3339Nano)}); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		if err := store.flush(); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	write(start, 10)
+	// Re-syncing an overlapping range replaces the stored rows.
+	write(start.Add(5*time.Minute), 10)
+
+	rows, err := readRows(filepath.Join(dir, "SPY", "bar", "1m", "20161004.csv"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(rows) != 15 {
+		t.Errorf("Expected %d rows, got %d", 15, len(rows))
+	}
+}
+
+func TestSyncResumesFromManifest(t *testing.T) {
+	open := time.Date(2016, 10, 4, 9, 30, 0, 0, calendar.Location)
+	var bars []*activetick.BarDataRecord
+	for i := 0; i < 120; i++ {
+		bars = append(bars, &activetick.BarDataRecord{
+			Time: open.Add(time.Duration(i) * time.Minute),
+			Open: 1, High: 1, Low: 1, Close: 1, Volume: int64(i),
+		})
+	}
+
+	server := attest.NewServer()
+	defer server.Close()
+	server.SetDataset("SPY", &attest.Dataset{Bars: bars})
+	client := activetick.NewPagingClient(activetick.NewClient(server.Client(), server.URL))
+
+	dir := t.TempDir()
+	sync := func(end time.Time) {
+		m, err := loadManifest(dir)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		err = syncSymbols(context.Background(), client, m, []string{"SPY"}, syncOptions{
+			dir:       dir,
+			dataTypes: []string{"bar"},
+			minutes:   5,
+			since:     open,
+			end:       end,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// The bar starting at 10:15 is incomplete, so is not stored.
+	sync(open.Add(45*time.Minute + 30*time.Second))
+	m, err := loadManifest(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	state := m.state("SPY", "bar/5m")
+	if state == nil || !state.Through.Equal(open.Add(45*time.Minute-time.Second)) {
+		t.Fatalf("Expected to have synced through 10:14:59, got %+v", state)
+	}
+
+	path := filepath.Join(dir, "SPY", "bar", "5m", "20161004.csv")
+	if rows, err := readRows(path); err != nil || len(rows) != 45 {
+		t.Fatalf("Expected %d rows, got %d: %v", 45, len(rows), err)
+	}
+
+	sync(open.Add(2 * time.Hour))
+	requests := server.Requests()
+	resumed := requests[len(requests)-1].Query().Get("beginTime")
+	if resumed != "20161004101500" {
+		t.Errorf("Expected to resume from %v, got %v", "20161004101500", resumed)
+	}
+
+	rows, err := readRows(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(rows) != len(bars) {
+		t.Fatalf("Expected %d rows, got %d", len(bars), len(rows))
+	}
+
+	for i, row := range rows {
+		if expected := barRow(bars[i]); !reflect.DeepEqual(row, expected) {
+			t.Errorf("Row %d: expected %v, got %v", i, expected, row)
+		}
+	}
+}
@@ -0,0 +1,130 @@
+/*
+Package atparquet writes ActiveTick bars and ticks to Parquet files.
+
+Timestamps are stored with millisecond precision, prices as float64 and
+sizes as int64. Symbols, tick types, exchanges and trade conditions are
+dictionary encoded.
+*/
+package atparquet
+
+import (
+	"io"
+	"time"
+
+	"github.com/parquet-go/parquet-go"
+	"github.com/timpalpant/go-activetick"
+)
+
+// Bar is the Parquet schema of a bar.
+type Bar struct {
+	Symbol string    `parquet:"symbol,dict"`
+	Time   time.Time `parquet:"time,timestamp(millisecond)"`
+	Open   float64   `parquet:"open"`
+	High   float64   `parquet:"high"`
+	Low    float64   `parquet:"low"`
+	Close  float64   `parquet:"close"`
+	Volume int64     `parquet:"volume"`
+}
+
+// Tick is the Parquet schema of a trade or quote. Columns that do not
+// apply to the tick's type are zero. Quotes store their quote condition
+// in Condition1.
+type Tick struct {
+	Symbol       string    `parquet:"symbol,dict"`
+	Type         string    `parquet:"type,dict"`
+	Time         time.Time `parquet:"time,timestamp(millisecond)"`
+	LastPrice    float64   `parquet:"last_price"`
+	LastSize     int64     `parquet:"last_size"`
+	LastExchange string    `parquet:"last_exchange,dict"`
+	Condition1   int32     `parquet:"condition1,dict"`
+	Condition2   int32     `parquet:"condition2,dict"`
+	Condition3   int32     `parquet:"condition3,dict"`
+	Condition4   int32     `parquet:"condition4,dict"`
+	BidPrice     float64   `parquet:"bid_price"`
+	AskPrice     float64   `parquet:"ask_price"`
+	BidSize      int64     `parquet:"bid_size"`
+	AskSize      int64     `parquet:"ask_size"`
+	BidExchange  string    `parquet:"bid_exchange,dict"`
+	AskExchange  string    `parquet:"ask_exchange,dict"`
+}
+
+func NewBar(symbol string, record *activetick.BarDataRecord) Bar {
+	return Bar{
+		Symbol: symbol,
+		Time:   record.Time,
+		Open:   record.Open,
+		High:   record.High,
+		Low:    record.Low,
+		Close:  record.Close,
+		Volume: record.Volume,
+	}
+}
+
+func NewTick(symbol string, record *activetick.TickRecord) Tick {
+	return Tick{
+		Symbol:       symbol,
+		Type:         string(record.Type),
+		Time:         record.Time,
+		LastPrice:    record.LastPrice,
+		LastSize:     record.LastSize,
+		LastExchange: string(record.LastExchange),
+		Condition1:   int32(record.Condition[0]),
+		Condition2:   int32(record.Condition[1]),
+		Condition3:   int32(record.Condition[2]),
+		Condition4:   int32(record.Condition[3]),
+		BidPrice:     record.BidPrice,
+		AskPrice:     record.AskPrice,
+		BidSize:      record.BidSize,
+		AskSize:      record.AskSize,
+		BidExchange:  string(record.BidExchange),
+		AskExchange:  string(record.AskExchange),
+	}
+}
+
+// BarWriter writes a stream of bars to a Parquet file.
+// Close must be called to write the file footer.
+type BarWriter struct {
+	w *parquet.GenericWriter[Bar]
+}
+
+func NewBarWriter(w io.Writer) *BarWriter {
+	return &BarWriter{parquet.NewGenericWriter[Bar](w)}
+}
+
+func (bw *BarWriter) Write(symbol string, records ...*activetick.BarDataRecord) error {
+	rows := make([]Bar, len(records))
+	for i, record := range records {
+		rows[i] = NewBar(symbol, record)
+	}
+
+	_, err := bw.w.Write(rows)
+	return err
+}
+
+func (bw *BarWriter) Close() error {
+	return bw.w.Close()
+}
+
+// TickWriter writes a stream of ticks to a Parquet file.
+// Close must be called to write the file footer.
+type TickWriter struct {
+	w *parquet.GenericWriter[Tick]
+}
+
+func NewTickWriter(w io.Writer) *TickWriter {
+	return &TickWriter{parquet.NewGenericWriter[Tick](w)}
+}
+
+func (tw *TickWriter) Write(symbol string, records ...*activetick.TickRecord) error {
+	rows := make([]Tick, len(records))
+	for i, record := range records {
+		rows[i] = NewTick(symbol, record)
+	}
+
+	_, err := tw.w.Write(rows)
+	return err
+}
+
+func (tw *TickWriter) Close() error {
+	return tw.w.Close()
+}
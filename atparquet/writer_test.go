@@ -0,0 +1,91 @@
+package atparquet
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/parquet-go/parquet-go"
+	"github.com/timpalpant/go-activetick"
+)
+
+func TestTickWriter(t *testing.T) {
+	start := time.Date(2012, 8, 3, 19, 30, 0, 551e6, time.UTC)
+	records := []*activetick.TickRecord{
+		{
+			Type:         activetick.TickTypeTrade,
+			Time:         start,
+			LastPrice:    616.55,
+			LastSize:     100,
+			LastExchange: activetick.ExchangeBatsYExchange,
+			Condition:    [4]activetick.TradeCondition{0, 14, 0, 0},
+		},
+		{
+			Type:        activetick.TickTypeQuote,
+			Time:        start.Add(50 * time.Millisecond),
+			BidPrice:    616.5,
+			AskPrice:    616.6,
+			BidSize:     200,
+			AskSize:     300,
+			BidExchange: activetick.ExchangeNasdaqOmx,
+			AskExchange: activetick.ExchangeNyseArcaExchange,
+		},
+	}
+
+	var buf bytes.Buffer
+	w := NewTickWriter(&buf)
+	if err := w.Write("GOOG", records...); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	rows, err := parquet.Read[Tick](bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(rows) != len(records) {
+		t.Fatalf("Expected %d rows, got %d", len(records), len(rows))
+	}
+
+	for i, row := range rows {
+		expected := NewTick("GOOG", records[i])
+		if !row.Time.Equal(expected.Time) {
+			t.Errorf("Row %d: expected time %v, got %v", i, expected.Time, row.Time)
+		}
+
+		row.Time = expected.Time
+		if row != expected {
+			t.Errorf("Row %d: expected %+v, got %+v", i, expected, row)
+		}
+	}
+}
+
+func TestBarWriter(t *testing.T) {
+	records := []*activetick.BarDataRecord{
+		{Time: time.Date(2010, 11, 1, 13, 30, 0, 0, time.UTC), Open: 26.88, High: 26.9, Low: 26.86, Close: 26.89, Volume: 1175094},
+		{Time: time.Date(2010, 11, 1, 13, 31, 0, 0, time.UTC), Open: 26.89, High: 26.91, Low: 26.87, Close: 26.87, Volume: 283043},
+	}
+
+	var buf bytes.Buffer
+	w := NewBarWriter(&buf)
+	if err := w.Write("SPY", records...); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	rows, err := parquet.Read[Bar](bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(rows) != len(records) || rows[1].Volume != 283043 || rows[0].Symbol != "SPY" {
+		t.Errorf("Unexpected rows: %+v", rows)
+	}
+}
@@ -0,0 +1,105 @@
+package attest
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/timpalpant/go-activetick"
+)
+
+// quoteFieldValue returns the data type and encoded value of field,
+// or false if the field is unknown.
+func quoteFieldValue(q *activetick.QuoteSnapshotRecord, field activetick.QuoteField) (activetick.DataItemType, string, bool) {
+	price := func(v float64) (activetick.DataItemType, string, bool) {
+		return activetick.DataPrice, fmt.Sprintf("%f", v), true
+	}
+	integer := func(v int) (activetick.DataItemType, string, bool) {
+		return activetick.DataUInteger32, strconv.Itoa(v), true
+	}
+	long := func(v int) (activetick.DataItemType, string, bool) {
+		return activetick.DataUInteger64, strconv.Itoa(v), true
+	}
+	str := func(v string) (activetick.DataItemType, string, bool) {
+		return activetick.DataString, v, true
+	}
+	exchange := func(v activetick.Exchange) (activetick.DataItemType, string, bool) {
+		return activetick.DataByte, string(v), true
+	}
+	dateTime := func(v time.Time) (activetick.DataItemType, string, bool) {
+		return activetick.DataDateTime, formatTime(v), true
+	}
+
+	switch field {
+	case activetick.QuoteFieldSymbol:
+		return str(q.Symbol)
+	case activetick.QuoteFieldOpenPrice:
+		return price(q.OpenPrice)
+	case activetick.QuoteFieldPreviousClosePrice:
+		return price(q.PreviousClosePrice)
+	case activetick.QuoteFieldClosePrice:
+		return price(q.ClosePrice)
+	case activetick.QuoteFieldLastPrice:
+		return price(q.LastPrice)
+	case activetick.QuoteFieldBidPrice:
+		return price(q.BidPrice)
+	case activetick.QuoteFieldAskPrice:
+		return price(q.AskPrice)
+	case activetick.QuoteFieldHighPrice:
+		return price(q.HighPrice)
+	case activetick.QuoteFieldLowPrice:
+		return price(q.LowPrice)
+	case activetick.QuoteFieldDayHighPrice:
+		return price(q.DayHighPrice)
+	case activetick.QuoteFieldDayLowPrice:
+		return price(q.DayLowPrice)
+	case activetick.QuoteFieldPreMarketOpenPrice:
+		return price(q.PreMarketOpenPrice)
+	case activetick.QuoteFieldExtendedHoursLastPrice:
+		return price(q.ExtendedHoursLastPrice)
+	case activetick.QuoteFieldAfterMarketClosePrice:
+		return price(q.AfterMarketClosePrice)
+	case activetick.QuoteFieldBidExchange:
+		return exchange(q.BidExchange)
+	case activetick.QuoteFieldAskExchange:
+		return exchange(q.AskExchange)
+	case activetick.QuoteFieldLastExchange:
+		return exchange(q.LastExchange)
+	case activetick.QuoteFieldLastCondition:
+		return integer(q.LastCondition)
+	case activetick.QuoteFieldQuoteCondition:
+		return integer(q.QuoteCondition)
+	case activetick.QuoteFieldLastTradeDateTime:
+		return dateTime(q.LastTradeTime)
+	case activetick.QuoteFieldLastQuoteDateTime:
+		return dateTime(q.LastQuoteTime)
+	case activetick.QuoteFieldDayHighDateTime:
+		return dateTime(q.DayHighTime)
+	case activetick.QuoteFieldDayLowDateTime:
+		return dateTime(q.DayLowTime)
+	case activetick.QuoteFieldLastSize:
+		return integer(q.LastSize)
+	case activetick.QuoteFieldBidSize:
+		return integer(q.BidSize)
+	case activetick.QuoteFieldAskSize:
+		return integer(q.AskSize)
+	case activetick.QuoteFieldVolume:
+		return long(q.Volume)
+	case activetick.QuoteFieldPreMarketVolume:
+		return long(q.PreMarketVolume)
+	case activetick.QuoteFieldAfterMarketVolume:
+		return long(q.AfterMarketVolume)
+	case activetick.QuoteFieldTradeCount:
+		return integer(q.TradeCount)
+	case activetick.QuoteFieldPreMarketTradeCount:
+		return integer(q.PreMarketTradeCount)
+	case activetick.QuoteFieldAfterMarketTradeCount:
+		return integer(q.AfterMarketTradeCount)
+	case activetick.QuoteFieldFundamentalEquityName:
+		return activetick.DataUnicodeString, q.FundamentalEquityName, true
+	case activetick.QuoteFieldFundamentalEquityPrimaryExchange:
+		return exchange(q.FundamentalEquityPrimaryExchange)
+	default:
+		return 0, "", false
+	}
+}
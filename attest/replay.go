@@ -0,0 +1,151 @@
+package attest
+
+import (
+	"bytes"
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/timpalpant/go-activetick/internal/atomicfile"
+)
+
+// fixture is a recorded HTTP exchange.
+type fixture struct {
+	URL        string      `json:"url"`
+	StatusCode int         `json:"status_code"`
+	Status     string      `json:"status"`
+	Header     http.Header `json:"header"`
+	Body       string      `json:"body"`
+}
+
+// fixturePath returns the file in dir that holds the fixture for req.
+// The host is ignored, so fixtures can be replayed against any endpoint.
+func fixturePath(dir string, req *http.Request) string {
+	key := req.URL.Path + "?" + req.URL.Query().Encode()
+	sum := sha1.Sum([]byte(key))
+	name := strings.Trim(req.URL.Path, "/") + "-" + hex.EncodeToString(sum[:8]) + ".json"
+	return filepath.Join(dir, name)
+}
+
+// Recorder is an http.RoundTripper that saves each exchange with the server
+// as a fixture file in Dir, for later use with Replayer. Use it as the
+// Transport of the *http.Client passed to activetick.NewClient.
+//
+// A fixture is written when the response body is closed, and contains the
+// part of the body that was read. A /quoteStream response is therefore
+// recorded up to the point at which the stream was stopped.
+type Recorder struct {
+	// Transport performs the requests.
+	// If nil, http.DefaultTransport is used.
+	Transport http.RoundTripper
+	Dir       string
+}
+
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	transport := r.Transport
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+
+	resp, err := transport.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	resp.Body = &recordingBody{
+		body: resp.Body,
+		path: fixturePath(r.Dir, req),
+		fixture: fixture{
+			URL:        req.URL.String(),
+			StatusCode: resp.StatusCode,
+			Status:     resp.Status,
+			Header:     resp.Header,
+		},
+	}
+
+	return resp, nil
+}
+
+type recordingBody struct {
+	body    io.ReadCloser
+	buf     bytes.Buffer
+	path    string
+	fixture fixture
+
+	closeOnce sync.Once
+	closeErr  error
+}
+
+func (b *recordingBody) Read(p []byte) (int, error) {
+	n, err := b.body.Read(p)
+	b.buf.Write(p[:n])
+	return n, err
+}
+
+// Close closes the body and writes the fixture. Only the first call
+// has any effect.
+func (b *recordingBody) Close() error {
+	b.closeOnce.Do(func() {
+		b.closeErr = b.save()
+	})
+
+	return b.closeErr
+}
+
+func (b *recordingBody) save() error {
+	if err := b.body.Close(); err != nil {
+		return err
+	}
+
+	b.fixture.Body = b.buf.String()
+	buf, err := json.MarshalIndent(&b.fixture, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return atomicfile.Write(b.path, func(w io.Writer) error {
+		_, err := w.Write(buf)
+		return err
+	})
+}
+
+// Replayer is an http.RoundTripper that serves responses from the fixtures
+// saved by a Recorder in Dir, without contacting a server. Requests that
+// were not recorded fail with an error.
+type Replayer struct {
+	Dir string
+}
+
+func (r *Replayer) RoundTrip(req *http.Request) (*http.Response, error) {
+	buf, err := ioutil.ReadFile(fixturePath(r.Dir, req))
+	if os.IsNotExist(err) {
+		return nil, fmt.Errorf("No fixture recorded for %v", req.URL)
+	} else if err != nil {
+		return nil, err
+	}
+
+	var f fixture
+	if err := json.Unmarshal(buf, &f); err != nil {
+		return nil, err
+	}
+
+	return &http.Response{
+		Status:        f.Status,
+		StatusCode:    f.StatusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        f.Header,
+		Body:          ioutil.NopCloser(strings.NewReader(f.Body)),
+		ContentLength: int64(len(f.Body)),
+		Request:       req,
+	}, nil
+}
@@ -0,0 +1,111 @@
+package attest_test
+
+import (
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/timpalpant/go-activetick"
+	"github.com/timpalpant/go-activetick/attest"
+)
+
+func TestRecordReplay(t *testing.T) {
+	server := attest.NewServer()
+	server.SetDataset("SPY", &attest.Dataset{
+		Bars:  makeBars(attest.MaxBars + 5),
+		Ticks: makeTicks(100),
+	})
+
+	dir := t.TempDir()
+	barReq := &activetick.BarDataRequest{
+		Symbol:          "SPY",
+		HistoryType:     activetick.HistoryTypeIntraday,
+		IntradayMinutes: 1,
+		BeginTime:       start,
+		EndTime:         start.Add(365 * 24 * time.Hour),
+	}
+	tickReq := &activetick.TickDataRequest{
+		Symbol:    "SPY",
+		Trades:    true,
+		Quotes:    true,
+		BeginTime: start,
+		EndTime:   start.Add(time.Hour),
+	}
+
+	recorder := &http.Client{Transport: &attest.Recorder{Dir: dir}}
+	client := activetick.NewPagingClient(activetick.NewClient(recorder, server.URL))
+	bars, err := client.GetBarData(barReq)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ticks, err := client.GetTickData(tickReq)
+	if err != nil {
+		t.Fatal(err)
+	}
+	server.Close()
+
+	replayer := &http.Client{Transport: &attest.Replayer{Dir: dir}}
+	client = activetick.NewPagingClient(activetick.NewClient(replayer, "http://replay"))
+	replayedBars, err := client.GetBarData(barReq)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	replayedTicks, err := client.GetTickData(tickReq)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(replayedBars.Records) != len(bars.Records) || len(replayedTicks.Records) != len(ticks.Records) {
+		t.Errorf("Expected %d bars and %d ticks, replayed %d and %d",
+			len(bars.Records), len(ticks.Records),
+			len(replayedBars.Records), len(replayedTicks.Records))
+	}
+
+	tickReq.Symbol = "QQQ"
+	if _, err := client.GetTickData(tickReq); err == nil {
+		t.Error("Expected error for request without a fixture")
+	}
+}
+
+func TestRecorderCloseTwice(t *testing.T) {
+	server := attest.NewServer()
+	defer server.Close()
+
+	dir := t.TempDir()
+	recorder := &attest.Recorder{Dir: dir}
+	req, err := http.NewRequest("GET", server.URL+"/optionChain?symbol=SPY", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	resp, err := recorder.RoundTrip(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := resp.Body.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	paths, err := filepath.Glob(filepath.Join(dir, "*"))
+	if err != nil || len(paths) != 1 {
+		t.Fatalf("Expected one fixture, got %v: %v", paths, err)
+	}
+
+	// A second Close does not write the fixture again.
+	if err := os.Remove(paths[0]); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := resp.Body.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := os.Stat(paths[0]); !os.IsNotExist(err) {
+		t.Errorf("Expected fixture not to be rewritten, got %v", err)
+	}
+}
@@ -0,0 +1,362 @@
+/*
+Package attest provides a fake ActiveTick HTTP server for testing code that
+uses package activetick without a live feed.
+
+The server serves /barData, /tickData, /quoteData, /quoteStream and
+/optionChain from in-memory datasets, and truncates responses the same way
+as the real server: at most the latest 20,000 bars and the earliest
+100,000 ticks of the requested range are returned. Like the real server,
+request and response times are in America/New_York.
+
+	server := attest.NewServer()
+	defer server.Close()
+	server.SetDataset("SPY", &attest.Dataset{Bars: bars})
+
+	client := activetick.NewClient(server.Client(), server.URL)
+
+Recorder and Replayer capture exchanges with a real server as fixture files
+and play them back, so that edge cases seen in production can be turned
+into regression tests.
+*/
+package attest
+
+import (
+	"bufio"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/timpalpant/go-activetick"
+	"github.com/timpalpant/go-activetick/calendar"
+)
+
+const (
+	// MaxBars is the maximum number of bars returned for one request.
+	MaxBars = 20000
+	// MaxTicks is the maximum number of ticks returned for one request.
+	MaxTicks = 100000
+
+	timeFormat = "20060102150405"
+)
+
+// Dataset is the data served for a single symbol.
+// Bars and ticks must be sorted by time.
+type Dataset struct {
+	// Bars are returned for intraday /barData requests,
+	// regardless of the requested bar size.
+	Bars       []*activetick.BarDataRecord
+	DailyBars  []*activetick.BarDataRecord
+	WeeklyBars []*activetick.BarDataRecord
+	Ticks      []*activetick.TickRecord
+	// Quote is returned for /quoteData requests. If nil, the symbol is
+	// reported as unavailable.
+	Quote *activetick.QuoteSnapshotRecord
+	// Stream records (*activetick.TradeStreamRecord or
+	// *activetick.QuoteStreamRecord) are sent in order to /quoteStream
+	// subscribers, after which the stream is closed.
+	Stream  []interface{}
+	Options []*activetick.OptionContract
+}
+
+// Server is a fake ActiveTick HTTP server. Symbols without a
+// dataset have no data, and are reported as invalid by /quoteData.
+type Server struct {
+	*httptest.Server
+
+	mu       sync.Mutex
+	datasets map[string]*Dataset
+	requests []*url.URL
+}
+
+// NewServer starts and returns a new Server.
+// The caller should call Close when finished, to shut it down.
+func NewServer() *Server {
+	s := &Server{datasets: make(map[string]*Dataset)}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serveHTTP))
+	return s
+}
+
+// SetDataset sets the data served for symbol.
+func (s *Server) SetDataset(symbol string, dataset *Dataset) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.datasets[symbol] = dataset
+}
+
+// Requests returns the URLs of all requests received so far.
+func (s *Server) Requests() []*url.URL {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]*url.URL(nil), s.requests...)
+}
+
+func (s *Server) dataset(symbol string) (*Dataset, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	dataset, ok := s.datasets[symbol]
+	if !ok {
+		return &Dataset{}, false
+	}
+
+	return dataset, true
+}
+
+func (s *Server) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	s.requests = append(s.requests, r.URL)
+	s.mu.Unlock()
+
+	var err error
+	switch r.URL.Path {
+	case "/barData":
+		err = s.serveBarData(w, r.URL.Query())
+	case "/tickData":
+		err = s.serveTickData(w, r.URL.Query())
+	case "/quoteData":
+		err = s.serveQuoteData(w, r.URL.Query())
+	case "/quoteStream":
+		err = s.serveQuoteStream(w, r.URL.Query())
+	case "/optionChain":
+		err = s.serveOptionChain(w, r.URL.Query())
+	default:
+		http.NotFound(w, r)
+		return
+	}
+
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	}
+}
+
+func parseTimeRange(q url.Values) (time.Time, time.Time, error) {
+	begin, err := time.ParseInLocation(timeFormat, q.Get("beginTime"), calendar.Location)
+	if err != nil {
+		return begin, begin, err
+	}
+
+	end, err := time.ParseInLocation(timeFormat, q.Get("endTime"), calendar.Location)
+	return begin, end, err
+}
+
+func (s *Server) serveBarData(w http.ResponseWriter, q url.Values) error {
+	begin, end, err := parseTimeRange(q)
+	if err != nil {
+		return err
+	}
+
+	historyType, err := strconv.Atoi(q.Get("historyType"))
+	if err != nil {
+		return err
+	}
+
+	dataset, _ := s.dataset(q.Get("symbol"))
+	var bars []*activetick.BarDataRecord
+	switch activetick.HistoryType(historyType) {
+	case activetick.HistoryTypeIntraday:
+		bars = dataset.Bars
+	case activetick.HistoryTypeDaily:
+		bars = dataset.DailyBars
+	case activetick.HistoryTypeWeekly:
+		bars = dataset.WeeklyBars
+	default:
+		return fmt.Errorf("Invalid history type: %v", historyType)
+	}
+
+	var matched []*activetick.BarDataRecord
+	for _, bar := range bars {
+		if !bar.Time.Before(begin) && !bar.Time.After(end) {
+			matched = append(matched, bar)
+		}
+	}
+
+	// The latest bars are returned.
+	if len(matched) > MaxBars {
+		matched = matched[len(matched)-MaxBars:]
+	}
+
+	out := bufio.NewWriter(w)
+	for _, bar := range matched {
+		WriteBar(out, bar)
+	}
+
+	return out.Flush()
+}
+
+func (s *Server) serveTickData(w http.ResponseWriter, q url.Values) error {
+	begin, end, err := parseTimeRange(q)
+	if err != nil {
+		return err
+	}
+
+	trades := q.Get("trades") == "1"
+	quotes := q.Get("quotes") == "1"
+	dataset, _ := s.dataset(q.Get("symbol"))
+
+	out := bufio.NewWriter(w)
+	n := 0
+	for _, tick := range dataset.Ticks {
+		second := tick.Time.Truncate(time.Second)
+		if second.Before(begin) || second.After(end) {
+			continue
+		}
+
+		if (tick.Type == activetick.TickTypeTrade && !trades) ||
+			(tick.Type == activetick.TickTypeQuote && !quotes) {
+			continue
+		}
+
+		// The earliest ticks are returned.
+		if n == MaxTicks {
+			break
+		}
+
+		WriteTick(out, tick)
+		n++
+	}
+
+	return out.Flush()
+}
+
+func (s *Server) serveQuoteData(w http.ResponseWriter, q url.Values) error {
+	var fields []activetick.QuoteField
+	for _, f := range strings.Fields(q.Get("field")) {
+		field, err := strconv.Atoi(f)
+		if err != nil {
+			return err
+		}
+
+		fields = append(fields, activetick.QuoteField(field))
+	}
+
+	out := csv.NewWriter(w)
+	for _, symbol := range strings.Fields(q.Get("symbol")) {
+		dataset, ok := s.dataset(symbol)
+		switch {
+		case !ok:
+			out.Write([]string{symbol, strconv.Itoa(int(activetick.SymbolStatusInvalid))})
+		case dataset.Quote == nil:
+			out.Write([]string{symbol, strconv.Itoa(int(activetick.SymbolStatusUnavailable))})
+		default:
+			row := []string{symbol, strconv.Itoa(int(activetick.SymbolStatusSuccess))}
+			for _, field := range fields {
+				status := activetick.QuoteFieldStatusSuccess
+				dataType, value, ok := quoteFieldValue(dataset.Quote, field)
+				if !ok {
+					status, dataType, value = activetick.QuoteFieldStatusInvalid, activetick.DataString, ""
+				}
+
+				row = append(row, strconv.Itoa(int(field)), strconv.Itoa(int(status)),
+					strconv.Itoa(int(dataType)), value)
+			}
+			out.Write(row)
+		}
+	}
+
+	out.Flush()
+	return out.Error()
+}
+
+func (s *Server) serveQuoteStream(w http.ResponseWriter, q url.Values) error {
+	out := bufio.NewWriter(w)
+	for _, symbol := range strings.Fields(q.Get("symbol")) {
+		dataset, _ := s.dataset(symbol)
+		for _, record := range dataset.Stream {
+			switch r := record.(type) {
+			case *activetick.TradeStreamRecord:
+				fmt.Fprintf(out, "T,%s,%d,%d,%d,%d,%d,%s,%f,%d,%s\n", r.Symbol, r.Flags,
+					r.TradeConditions[0], r.TradeConditions[1], r.TradeConditions[2], r.TradeConditions[3],
+					r.LastExchange, r.LastPrice, r.LastSize, formatTime(r.LastDate))
+			case *activetick.QuoteStreamRecord:
+				fmt.Fprintf(out, "Q,%s,%d,%s,%s,%f,%f,%d,%d,%s\n", r.Symbol, r.QuoteCondition,
+					r.BidExchange, r.AskExchange, r.BidPrice, r.AskPrice,
+					r.BidSize, r.AskSize, formatTime(r.QuoteTime))
+			default:
+				return fmt.Errorf("Invalid stream record: %T", record)
+			}
+		}
+	}
+
+	return out.Flush()
+}
+
+func (s *Server) serveOptionChain(w http.ResponseWriter, q url.Values) error {
+	dataset, _ := s.dataset(q.Get("symbol"))
+	out := bufio.NewWriter(w)
+	for _, option := range dataset.Options {
+		fmt.Fprintln(out, OptionSymbol(option))
+	}
+
+	return out.Flush()
+}
+
+// OptionSymbol returns the option's Symbol, or if it is empty,
+// the ActiveTick option symbol constructed from its other fields.
+func OptionSymbol(option *activetick.OptionContract) string {
+	if option.Symbol != "" {
+		return option.Symbol
+	}
+
+	return fmt.Sprintf(".%-6s%s%s%08d", option.Underlying, option.Expiration.Format("060102"),
+		option.Type, int64(option.Strike*1000+0.5))
+}
+
+func formatTime(t time.Time) string {
+	t = t.In(calendar.Location)
+	return fmt.Sprintf("%s%03d", t.Format(timeFormat), t.Nanosecond()/int(time.Millisecond))
+}
+
+// WriteBar writes bar as a row of a /barData response.
+func WriteBar(w io.Writer, bar *activetick.BarDataRecord) error {
+	_, err := fmt.Fprintf(w, "%s,%f,%f,%f,%f,%d\n", bar.Time.In(calendar.Location).Format(timeFormat),
+		bar.Open, bar.High, bar.Low, bar.Close, bar.Volume)
+	return err
+}
+
+// WriteTick writes tick as a row of a /tickData response.
+func WriteTick(w io.Writer, tick *activetick.TickRecord) error {
+	var err error
+	switch tick.Type {
+	case activetick.TickTypeTrade:
+		_, err = fmt.Fprintf(w, "T,%s,%f,%d,%s,%d,%d,%d,%d\n", formatTime(tick.Time),
+			tick.LastPrice, tick.LastSize, tick.LastExchange,
+			tick.Condition[0], tick.Condition[1], tick.Condition[2], tick.Condition[3])
+	case activetick.TickTypeQuote:
+		_, err = fmt.Fprintf(w, "Q,%s,%f,%f,%d,%d,%s,%s,%d\n", formatTime(tick.Time),
+			tick.BidPrice, tick.AskPrice, tick.BidSize, tick.AskSize,
+			tick.BidExchange, tick.AskExchange, tick.Condition[0])
+	default:
+		err = fmt.Errorf("Unknown tick type: %v", tick.Type)
+	}
+
+	return err
+}
+
+// LoadBars reads bars from a file in the format of a /barData response.
+func LoadBars(path string) ([]*activetick.BarDataRecord, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	return activetick.ReadBarData(f)
+}
+
+// LoadTicks reads ticks from a file in the format of a /tickData response.
+func LoadTicks(path string) ([]*activetick.TickRecord, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	return activetick.ReadTickData(f)
+}
@@ -0,0 +1,263 @@
+package attest_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/timpalpant/go-activetick"
+	"github.com/timpalpant/go-activetick/attest"
+)
+
+var start = time.Date(2016, 10, 4, 13, 30, 0, 0, time.UTC)
+
+func makeBars(n int) []*activetick.BarDataRecord {
+	bars := make([]*activetick.BarDataRecord, n)
+	for i := range bars {
+		bars[i] = &activetick.BarDataRecord{
+			Time:   start.Add(time.Duration(i) * time.Minute),
+			Open:   100,
+			High:   101,
+			Low:    99,
+			Close:  100.5,
+			Volume: int64(i),
+		}
+	}
+
+	return bars
+}
+
+func makeTicks(n int) []*activetick.TickRecord {
+	ticks := make([]*activetick.TickRecord, n)
+	for i := range ticks {
+		t := start.Add(time.Duration(i) * 100 * time.Millisecond)
+		if i%2 == 0 {
+			ticks[i] = &activetick.TickRecord{
+				Type:         activetick.TickTypeTrade,
+				Time:         t,
+				LastPrice:    100,
+				LastSize:     int64(i),
+				LastExchange: activetick.ExchangeNasdaqOmx,
+			}
+		} else {
+			ticks[i] = &activetick.TickRecord{
+				Type:        activetick.TickTypeQuote,
+				Time:        t,
+				BidPrice:    99.99,
+				AskPrice:    100.01,
+				BidSize:     int64(i),
+				AskSize:     int64(i),
+				BidExchange: activetick.ExchangeNyseArcaExchange,
+				AskExchange: activetick.ExchangeNasdaqOmx,
+			}
+		}
+	}
+
+	return ticks
+}
+
+func TestBarDataTruncation(t *testing.T) {
+	server := attest.NewServer()
+	defer server.Close()
+	server.SetDataset("SPY", &attest.Dataset{Bars: makeBars(attest.MaxBars + 10)})
+
+	req := &activetick.BarDataRequest{
+		Symbol:          "SPY",
+		HistoryType:     activetick.HistoryTypeIntraday,
+		IntradayMinutes: 1,
+		BeginTime:       start,
+		EndTime:         start.Add(365 * 24 * time.Hour),
+	}
+
+	client := activetick.NewClient(server.Client(), server.URL)
+	resp, err := client.GetBarData(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(resp.Records) != attest.MaxBars || resp.Records[0].Volume != 10 {
+		t.Errorf("Expected the latest %d bars, got %d starting from %v",
+			attest.MaxBars, len(resp.Records), resp.Records[0].Volume)
+	}
+
+	paged, err := activetick.NewPagingClient(client).GetBarData(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(paged.Records) != attest.MaxBars+10 {
+		t.Errorf("Expected %d bars, got %d", attest.MaxBars+10, len(paged.Records))
+	}
+
+	q := server.Requests()[0].Query()
+	if q.Get("symbol") != "SPY" || q.Get("beginTime") != "20161004093000" || q.Get("intradayMinutes") != "1" {
+		t.Errorf("Unexpected request parameters: %v", q)
+	}
+}
+
+func TestTickDataTruncation(t *testing.T) {
+	server := attest.NewServer()
+	defer server.Close()
+	server.SetDataset("SPY", &attest.Dataset{Ticks: makeTicks(2*attest.MaxTicks + 3)})
+
+	req := &activetick.TickDataRequest{
+		Symbol:    "SPY",
+		Trades:    true,
+		Quotes:    true,
+		BeginTime: start,
+		EndTime:   start.Add(24 * time.Hour),
+	}
+
+	client := activetick.NewClient(server.Client(), server.URL)
+	resp, err := client.GetTickData(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(resp.Records) != attest.MaxTicks || resp.Records[0].LastSize != 0 {
+		t.Errorf("Expected the earliest %d ticks, got %d", attest.MaxTicks, len(resp.Records))
+	}
+
+	paged, err := activetick.NewPagingClient(client).GetTickData(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(paged.Records) != 2*attest.MaxTicks+3 {
+		t.Errorf("Expected %d ticks, got %d", 2*attest.MaxTicks+3, len(paged.Records))
+	}
+
+	req.Quotes = false
+	trades, err := activetick.NewPagingClient(client).GetTickData(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(trades.Records) != attest.MaxTicks+2 {
+		t.Errorf("Expected %d trades, got %d", attest.MaxTicks+2, len(trades.Records))
+	}
+}
+
+func TestQuoteData(t *testing.T) {
+	server := attest.NewServer()
+	defer server.Close()
+	server.SetDataset("AAPL", &attest.Dataset{
+		Quote: &activetick.QuoteSnapshotRecord{
+			Symbol:        "AAPL",
+			LastPrice:     615.69,
+			LastExchange:  activetick.ExchangeBatsExchange,
+			Volume:        12024155,
+			LastTradeTime: start.Add(1500 * time.Millisecond),
+		},
+	})
+
+	client := activetick.NewClient(server.Client(), server.URL)
+	resp, err := client.GetQuoteData(&activetick.QuoteDataRequest{
+		Symbols: []string{"AAPL", "ZZZZ"},
+		QuoteFields: []activetick.QuoteField{
+			activetick.QuoteFieldLastPrice,
+			activetick.QuoteFieldLastExchange,
+			activetick.QuoteFieldVolume,
+			activetick.QuoteFieldLastTradeDateTime,
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(resp.Records) != 2 {
+		t.Fatalf("Expected %d records, got %d", 2, len(resp.Records))
+	}
+
+	aapl := resp.Records[0]
+	if aapl.Status != activetick.SymbolStatusSuccess || aapl.LastPrice != 615.69 ||
+		aapl.LastExchange != activetick.ExchangeBatsExchange || aapl.Volume != 12024155 ||
+		!aapl.LastTradeTime.Equal(start.Add(1500*time.Millisecond)) {
+		t.Errorf("Unexpected quote: %+v", aapl)
+	}
+
+	if resp.Records[1].Status != activetick.SymbolStatusInvalid {
+		t.Errorf("Expected invalid symbol, got %+v", resp.Records[1])
+	}
+}
+
+func TestQuoteDataQuoting(t *testing.T) {
+	server := attest.NewServer()
+	defer server.Close()
+	symbol := `BRK,"A"`
+	server.SetDataset(symbol, &attest.Dataset{
+		Quote: &activetick.QuoteSnapshotRecord{Symbol: symbol, LastPrice: 1},
+	})
+
+	client := activetick.NewClient(server.Client(), server.URL)
+	resp, err := client.GetQuoteData(&activetick.QuoteDataRequest{
+		Symbols: []string{symbol},
+		QuoteFields: []activetick.QuoteField{
+			activetick.QuoteFieldSymbol,
+			activetick.QuoteFieldLastPrice,
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(resp.Records) != 1 || resp.Records[0].Symbol != symbol || resp.Records[0].LastPrice != 1 {
+		t.Errorf("Unexpected quotes: %+v", resp.Records)
+	}
+}
+
+type streamCounter struct {
+	trades, quotes int
+}
+
+func (c *streamCounter) HandleTrade(*activetick.TradeStreamRecord) { c.trades++ }
+func (c *streamCounter) HandleQuote(*activetick.QuoteStreamRecord) { c.quotes++ }
+
+func TestQuoteStream(t *testing.T) {
+	server := attest.NewServer()
+	defer server.Close()
+	server.SetDataset("SPY", &attest.Dataset{
+		Stream: []interface{}{
+			&activetick.QuoteStreamRecord{Symbol: "SPY", BidPrice: 139.34, AskPrice: 139.35, QuoteTime: start},
+			&activetick.TradeStreamRecord{Symbol: "SPY", LastPrice: 139.35, LastSize: 100, LastDate: start},
+		},
+	})
+
+	client := activetick.NewClient(server.Client(), server.URL)
+	counter := &streamCounter{}
+	req := &activetick.QuoteStreamRequest{Symbols: []string{"SPY"}}
+	if err := client.StreamQuotes(context.Background(), req, counter); err != nil {
+		t.Fatal(err)
+	}
+
+	if counter.trades != 1 || counter.quotes != 1 {
+		t.Errorf("Expected 1 trade and 1 quote, got %+v", counter)
+	}
+}
+
+func TestOptionChain(t *testing.T) {
+	server := attest.NewServer()
+	defer server.Close()
+	expiration := time.Date(2012, 10, 19, 0, 0, 0, 0, time.UTC)
+	server.SetDataset("AAPL", &attest.Dataset{
+		Options: []*activetick.OptionContract{
+			{Underlying: "AAPL", Expiration: expiration, Type: activetick.OptionTypeCall, Strike: 617.5},
+			{Underlying: "AAPL", Expiration: expiration, Type: activetick.OptionTypePut, Strike: 500},
+		},
+	})
+
+	client := activetick.NewClient(server.Client(), server.URL)
+	resp, err := client.GetOptionChain(&activetick.OptionChainRequest{Symbol: "AAPL"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(resp.Records) != 2 {
+		t.Fatalf("Expected %d options, got %d", 2, len(resp.Records))
+	}
+
+	call := resp.Records[0]
+	if call.Symbol != ".AAPL  121019C00617500" || call.Strike != 617.5 || call.Type != activetick.OptionTypeCall {
+		t.Errorf("Unexpected option: %+v", call)
+	}
+}
@@ -0,0 +1,83 @@
+package activetick
+
+import (
+	"context"
+	"sync"
+)
+
+// BatchClient fetches data for many symbols concurrently, with at most
+// a fixed number of symbols being fetched at once.
+type BatchClient struct {
+	client      *PagingClient
+	parallelism int
+}
+
+func NewBatchClient(client *PagingClient, parallelism int) *BatchClient {
+	if parallelism < 1 {
+		parallelism = 1
+	}
+
+	return &BatchClient{client, parallelism}
+}
+
+// BarDataResult holds the response or error for one symbol of a batch.
+type BarDataResult struct {
+	Symbol   string
+	Response *BarDataResponse
+	Err      error
+}
+
+// TickDataResult holds the response or error for one symbol of a batch.
+type TickDataResult struct {
+	Symbol   string
+	Response *TickDataResponse
+	Err      error
+}
+
+// GetBarData fetches bars for each of the given symbols, using req for all
+// other request parameters (its Symbol is ignored). Results are returned
+// in the same order as symbols; a failure for one symbol does not affect
+// the others.
+func (bc *BatchClient) GetBarData(ctx context.Context, symbols []string, req *BarDataRequest) []*BarDataResult {
+	results := make([]*BarDataResult, len(symbols))
+	bc.forEach(ctx, symbols, func(i int, symbol string) {
+		symbolReq := *req
+		symbolReq.Symbol = symbol
+		resp, err := bc.client.GetBarDataContext(ctx, &symbolReq)
+		results[i] = &BarDataResult{symbol, resp, err}
+	})
+
+	return results
+}
+
+// GetTickData fetches ticks for each of the given symbols, using req for
+// all other request parameters (its Symbol is ignored). Results are
+// returned in the same order as symbols; a failure for one symbol does
+// not affect the others.
+func (bc *BatchClient) GetTickData(ctx context.Context, symbols []string, req *TickDataRequest) []*TickDataResult {
+	results := make([]*TickDataResult, len(symbols))
+	bc.forEach(ctx, symbols, func(i int, symbol string) {
+		symbolReq := *req
+		symbolReq.Symbol = symbol
+		resp, err := bc.client.GetTickDataContext(ctx, &symbolReq)
+		results[i] = &TickDataResult{symbol, resp, err}
+	})
+
+	return results
+}
+
+// forEach calls fn for each symbol, running at most bc.parallelism at once.
+func (bc *BatchClient) forEach(ctx context.Context, symbols []string, fn func(int, string)) {
+	sem := make(chan struct{}, bc.parallelism)
+	var wg sync.WaitGroup
+	for i, symbol := range symbols {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int, symbol string) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			fn(i, symbol)
+		}(i, symbol)
+	}
+	wg.Wait()
+}
@@ -0,0 +1,38 @@
+package activetick
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestBatchGetBarData(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("symbol") == "INVALID" {
+			http.Error(w, "invalid symbol", http.StatusBadRequest)
+			return
+		}
+
+		fmt.Fprintln(w, "20101101093000,26.880000,26.900000,26.860000,26.890000,1175094")
+	}))
+	defer server.Close()
+
+	client := NewBatchClient(NewPagingClient(NewClient(server.Client(), server.URL)), 2)
+	symbols := []string{"SPY", "INVALID", "QQQ", "AAPL"}
+	results := client.GetBarData(context.Background(), symbols, &BarDataRequest{})
+	if len(results) != len(symbols) {
+		t.Fatalf("Expected %d results, got %d", len(symbols), len(results))
+	}
+
+	for i, result := range results {
+		if result.Symbol != symbols[i] {
+			t.Errorf("Expected result %d for %v, got %v", i, symbols[i], result.Symbol)
+		}
+
+		if failed := result.Err != nil; failed != (result.Symbol == "INVALID") {
+			t.Errorf("Unexpected error for %v: %v", result.Symbol, result.Err)
+		}
+	}
+}
@@ -0,0 +1,203 @@
+package activetick
+
+import (
+	"context"
+	"encoding/gob"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"github.com/timpalpant/go-activetick/internal/atomicfile"
+)
+
+// CachingClient wraps PagingClient to store historical intraday bars and
+// ticks in a local directory. Data is cached one calendar day at a time,
+// in the location of the server (see WithLocation), and only once that day
+// has ended, so repeated requests for past data are served from disk and
+// only the missing days are fetched from the server.
+//
+// Daily and weekly bars are not cached.
+type CachingClient struct {
+	client *PagingClient
+	dir    string
+	now    func() time.Time
+}
+
+// cacheVersion is part of the cache path, and is incremented whenever
+// the encoding or meaning of cached data changes, so that files written
+// by older versions are never read. Before version 2, enums were encoded
+// as numbers rather than by name and times were parsed as UTC rather than
+// in the server's location. Before version 3, records were not tagged
+// with their session.
+const cacheVersion = "v3"
+
+func NewCachingClient(client *PagingClient, dir string) *CachingClient {
+	return &CachingClient{client, dir, time.Now}
+}
+
+func (cc *CachingClient) GetBarData(req *BarDataRequest) (*BarDataResponse, error) {
+	return cc.GetBarDataContext(context.Background(), req)
+}
+
+// GetBarDataContext is like GetBarData, but stops fetching and cancels
+// any outstanding request when ctx is done.
+func (cc *CachingClient) GetBarDataContext(ctx context.Context, req *BarDataRequest) (*BarDataResponse, error) {
+	if req.HistoryType != HistoryTypeIntraday {
+		return cc.client.GetBarDataContext(ctx, req)
+	}
+
+	resp := &BarDataResponse{
+		Records: []*BarDataRecord{},
+	}
+
+	for _, day := range cacheDays(req.BeginTime.In(cc.client.client.location), req.EndTime) {
+		path := filepath.Join(cc.dir, cacheVersion, "bars", url.PathEscape(req.Symbol),
+			strconv.Itoa(req.IntradayMinutes)+"m", day.Format("20060102")+".gob")
+
+		var records []*BarDataRecord
+		err := cc.load(path, day, &records, func() error {
+			page, err := cc.client.GetBarDataContext(ctx, &BarDataRequest{
+				Symbol:          req.Symbol,
+				HistoryType:     req.HistoryType,
+				IntradayMinutes: req.IntradayMinutes,
+				BeginTime:       day,
+				EndTime:         endOfDay(day),
+			})
+			if err != nil {
+				return err
+			}
+
+			records = page.Records
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		for _, record := range records {
+			if !record.Time.Before(req.BeginTime) && !record.Time.After(req.EndTime) {
+				resp.Records = append(resp.Records, record)
+			}
+		}
+	}
+
+	return resp, nil
+}
+
+func (cc *CachingClient) GetTickData(req *TickDataRequest) (*TickDataResponse, error) {
+	return cc.GetTickDataContext(context.Background(), req)
+}
+
+// GetTickDataContext is like GetTickData, but stops fetching and cancels
+// any outstanding request when ctx is done.
+//
+// Whole days are fetched and decoded, even if only part of a day is
+// requested, and the current day is fetched from the server on every
+// call until it has ended. At least one of Trades and Quotes must be set.
+func (cc *CachingClient) GetTickDataContext(ctx context.Context, req *TickDataRequest) (*TickDataResponse, error) {
+	if !req.Trades && !req.Quotes {
+		return nil, errors.New("Invalid tick data request: neither trades nor quotes requested")
+	}
+
+	kind := ""
+	if req.Trades {
+		kind += "trades"
+	}
+	if req.Quotes {
+		kind += "quotes"
+	}
+
+	begin := req.BeginTime.Truncate(time.Second)
+	end := req.EndTime.Truncate(time.Second)
+	resp := &TickDataResponse{
+		Records: []*TickRecord{},
+	}
+
+	for _, day := range cacheDays(req.BeginTime.In(cc.client.client.location), req.EndTime) {
+		path := filepath.Join(cc.dir, cacheVersion, "ticks", url.PathEscape(req.Symbol),
+			kind, day.Format("20060102")+".gob")
+
+		var records []*TickRecord
+		err := cc.load(path, day, &records, func() error {
+			page, err := cc.client.GetTickDataContext(ctx, &TickDataRequest{
+				Symbol:    req.Symbol,
+				Trades:    req.Trades,
+				Quotes:    req.Quotes,
+				BeginTime: day,
+				EndTime:   endOfDay(day),
+			})
+			if err != nil {
+				return err
+			}
+
+			records = page.Records
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		for _, record := range records {
+			second := record.Time.Truncate(time.Second)
+			if !second.Before(begin) && !second.After(end) {
+				resp.Records = append(resp.Records, record)
+			}
+		}
+	}
+
+	return resp, nil
+}
+
+// load decodes the cached day at path into v. If it is not cached, fetch
+// is called to populate v, which is then saved if the day has ended.
+func (cc *CachingClient) load(path string, day time.Time, v interface{}, fetch func() error) error {
+	f, err := os.Open(path)
+	if err == nil {
+		defer f.Close()
+		if err := gob.NewDecoder(f).Decode(v); err != nil {
+			return fmt.Errorf("Corrupt cache file %v: %w", path, err)
+		}
+
+		return nil
+	} else if !os.IsNotExist(err) {
+		return err
+	}
+
+	if err := fetch(); err != nil {
+		return err
+	}
+
+	if !cc.now().After(endOfDay(day)) {
+		return nil
+	}
+
+	return writeGob(path, v)
+}
+
+// writeGob atomically writes v to path, creating its directory if needed.
+func writeGob(path string, v interface{}) error {
+	return atomicfile.Write(path, func(w io.Writer) error {
+		return gob.NewEncoder(w).Encode(v)
+	})
+}
+
+// cacheDays returns the start of each calendar day overlapping [begin, end].
+func cacheDays(begin, end time.Time) []time.Time {
+	var days []time.Time
+	y, m, d := begin.Date()
+	for day := time.Date(y, m, d, 0, 0, 0, 0, begin.Location()); !day.After(end); day = day.AddDate(0, 0, 1) {
+		days = append(days, day)
+	}
+
+	return days
+}
+
+// endOfDay returns the last second of the day starting at day.
+func endOfDay(day time.Time) time.Time {
+	return day.AddDate(0, 0, 1).Add(-time.Second)
+}
@@ -0,0 +1,164 @@
+package activetick_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/timpalpant/go-activetick"
+	"github.com/timpalpant/go-activetick/attest"
+)
+
+// newCachingServer returns a server with dataset for SPY, and a caching
+// client for it that stores data in dir.
+func newCachingServer(dataset *attest.Dataset, dir string) (*attest.Server, *activetick.CachingClient) {
+	server, client := newPagingServer(dataset)
+	return server, activetick.NewCachingClient(client, dir)
+}
+
+func TestCachingClientBarData(t *testing.T) {
+	server, client := newCachingServer(&attest.Dataset{Bars: makeBars(pagingStart, 5000)}, t.TempDir())
+	defer server.Close()
+
+	req := &activetick.BarDataRequest{
+		Symbol:          "SPY",
+		HistoryType:     activetick.HistoryTypeIntraday,
+		IntradayMinutes: 1,
+		BeginTime:       pagingStart.Add(time.Hour),
+		EndTime:         pagingStart.Add(30 * time.Hour),
+	}
+
+	first, err := client.GetBarData(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(server.Requests()) != 2 {
+		t.Errorf("Expected %d requests, got %d", 2, len(server.Requests()))
+	}
+
+	second, err := client.GetBarData(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(server.Requests()) != 2 {
+		t.Errorf("Expected cached response, got %d requests", len(server.Requests()))
+	}
+
+	if len(first.Records) != 29*60+1 || len(second.Records) != len(first.Records) {
+		t.Errorf("Expected %d records, got %d and %d",
+			29*60+1, len(first.Records), len(second.Records))
+	}
+
+	// Extending the range should only fetch the missing day.
+	req.EndTime = pagingStart.Add(50 * time.Hour)
+	if _, err := client.GetBarData(req); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(server.Requests()) != 3 {
+		t.Errorf("Expected %d requests, got %d", 3, len(server.Requests()))
+	}
+}
+
+func TestCachingClientTickData(t *testing.T) {
+	server, client := newCachingServer(&attest.Dataset{Ticks: makeTicks(pagingStart, 1000, 2)}, t.TempDir())
+	defer server.Close()
+
+	req := &activetick.TickDataRequest{
+		Symbol:    "SPY",
+		Trades:    true,
+		BeginTime: pagingStart,
+		EndTime:   pagingStart.Add(time.Minute),
+	}
+
+	for i := 0; i < 2; i++ {
+		resp, err := client.GetTickData(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(resp.Records) != 122 {
+			t.Errorf("Expected %d records, got %d", 122, len(resp.Records))
+		}
+	}
+
+	if len(server.Requests()) != 1 {
+		t.Errorf("Expected %d requests, got %d", 1, len(server.Requests()))
+	}
+}
+
+func TestCachingClientNoTradesOrQuotes(t *testing.T) {
+	server, client := newCachingServer(&attest.Dataset{Ticks: makeTicks(pagingStart, 10, 2)}, t.TempDir())
+	defer server.Close()
+
+	_, err := client.GetTickData(&activetick.TickDataRequest{
+		Symbol:    "SPY",
+		BeginTime: pagingStart,
+		EndTime:   pagingStart.Add(time.Minute),
+	})
+	if err == nil {
+		t.Error("Expected error requesting neither trades nor quotes")
+	}
+
+	if len(server.Requests()) != 0 {
+		t.Errorf("Expected no requests, got %d", len(server.Requests()))
+	}
+}
+
+func TestCachingClientSkipsIncompleteDays(t *testing.T) {
+	now := time.Now().UTC()
+	server, client := newCachingServer(&attest.Dataset{Bars: makeBars(now.Add(-time.Hour), 30)}, t.TempDir())
+	defer server.Close()
+
+	req := &activetick.BarDataRequest{
+		Symbol:          "SPY",
+		HistoryType:     activetick.HistoryTypeIntraday,
+		IntradayMinutes: 1,
+		BeginTime:       now.Add(-2 * time.Hour),
+		EndTime:         now,
+	}
+
+	for i := 0; i < 2; i++ {
+		if _, err := client.GetBarData(req); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if len(server.Requests()) < 2 {
+		t.Errorf("Expected today's bars not to be cached, got %d requests", len(server.Requests()))
+	}
+}
+
+func TestCachingClientCorruptFile(t *testing.T) {
+	dir := t.TempDir()
+	server, client := newCachingServer(&attest.Dataset{Bars: makeBars(pagingStart, 100)}, dir)
+	defer server.Close()
+
+	req := &activetick.BarDataRequest{
+		Symbol:          "SPY",
+		HistoryType:     activetick.HistoryTypeIntraday,
+		IntradayMinutes: 1,
+		BeginTime:       pagingStart,
+		EndTime:         pagingStart.Add(time.Hour),
+	}
+
+	if _, err := client.GetBarData(req); err != nil {
+		t.Fatal(err)
+	}
+
+	paths, err := filepath.Glob(filepath.Join(dir, "*", "bars", "SPY", "1m", "*.gob"))
+	if err != nil || len(paths) != 1 {
+		t.Fatalf("Expected one cache file, got %v: %v", paths, err)
+	}
+
+	if err := os.Truncate(paths[0], 10); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := client.GetBarData(req); err == nil {
+		t.Error("Expected error reading truncated cache file")
+	}
+}
@@ -0,0 +1,357 @@
+/*
+Package calendar is a US equity trading calendar. It reports holidays,
+early closes and the trading session in effect at any time:
+
+	pre-market   04:00 - 09:30
+	regular      09:30 - 16:00 (13:00 on early close days)
+	after-hours  16:00 - 20:00 (13:00 - 17:00 on early close days)
+
+All times are US Eastern. Holidays follow NYSE rules, including
+Juneteenth from 2022 and unscheduled closures since 2001. Historical
+early closes that do not follow the current rules are not included.
+*/
+package calendar
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+	_ "time/tzdata"
+)
+
+// Location is America/New_York, the time zone of the calendar and of
+// the ActiveTick HTTP server. It is loaded from the embedded time zone
+// database.
+var Location = loadLocation()
+
+func loadLocation() *time.Location {
+	loc, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		panic(err)
+	}
+
+	return loc
+}
+
+// Session is a trading session of a day.
+type Session int
+
+const (
+	SessionClosed Session = iota
+	SessionPreMarket
+	SessionRegular
+	SessionAfterHours
+)
+
+var sessionNames = []string{
+	SessionClosed:     "closed",
+	SessionPreMarket:  "pre-market",
+	SessionRegular:    "regular",
+	SessionAfterHours: "after-hours",
+}
+
+func (s Session) String() string {
+	if s < 0 || int(s) >= len(sessionNames) {
+		return fmt.Sprintf("Session(%d)", int(s))
+	}
+
+	return sessionNames[s]
+}
+
+func (s Session) MarshalText() ([]byte, error) {
+	return []byte(s.String()), nil
+}
+
+func (s *Session) UnmarshalText(text []byte) error {
+	for i, name := range sessionNames {
+		if strings.EqualFold(string(text), name) {
+			*s = Session(i)
+			return nil
+		}
+	}
+
+	return fmt.Errorf("Invalid session: %q", text)
+}
+
+// Day is the schedule of a single trading day.
+type Day struct {
+	// Date is midnight at the start of the day.
+	Date            time.Time
+	PreMarketOpen   time.Time
+	Open            time.Time
+	Close           time.Time
+	AfterHoursClose time.Time
+	EarlyClose      bool
+}
+
+// Session returns the time range [begin, end) of session on the day.
+// The range is empty for SessionClosed.
+func (d *Day) Session(session Session) (begin, end time.Time) {
+	switch session {
+	case SessionPreMarket:
+		return d.PreMarketOpen, d.Open
+	case SessionRegular:
+		return d.Open, d.Close
+	case SessionAfterHours:
+		return d.Close, d.AfterHoursClose
+	default:
+		return d.Date, d.Date
+	}
+}
+
+// TradingDay returns the schedule of the day containing t (in Location),
+// or false if it is not a trading day.
+func TradingDay(t time.Time) (*Day, bool) {
+	t = t.In(Location)
+	y, m, d := t.Date()
+	date := time.Date(y, m, d, 0, 0, 0, 0, Location)
+	if isWeekend(date) {
+		return nil, false
+	}
+
+	kind := yearOf(y).days[dayKey(m, d)]
+	if kind == holiday {
+		return nil, false
+	}
+
+	at := func(hour, min int) time.Time {
+		return time.Date(y, m, d, hour, min, 0, 0, Location)
+	}
+
+	day := &Day{
+		Date:            date,
+		PreMarketOpen:   at(4, 0),
+		Open:            at(9, 30),
+		Close:           at(16, 0),
+		AfterHoursClose: at(20, 0),
+	}
+
+	if kind == earlyClose {
+		day.Close = at(13, 0)
+		day.AfterHoursClose = at(17, 0)
+		day.EarlyClose = true
+	}
+
+	return day, true
+}
+
+// IsTradingDay reports whether the day containing t (in Location)
+// is a trading day.
+func IsTradingDay(t time.Time) bool {
+	_, ok := TradingDay(t)
+	return ok
+}
+
+// IsHoliday reports whether the day containing t (in Location) is a
+// weekday on which the market is closed.
+func IsHoliday(t time.Time) bool {
+	t = t.In(Location)
+	y, m, d := t.Date()
+	return !isWeekend(t) && yearOf(y).days[dayKey(m, d)] == holiday
+}
+
+// IsEarlyClose reports whether the day containing t (in Location)
+// is a trading day on which the regular session ends at 13:00.
+func IsEarlyClose(t time.Time) bool {
+	day, ok := TradingDay(t)
+	return ok && day.EarlyClose
+}
+
+// SessionAt returns the session in effect at t. It is called for every
+// parsed record, so it compares the wall clock time with the session
+// hours rather than building the Day.
+func SessionAt(t time.Time) Session {
+	t = t.In(Location)
+	if isWeekend(t) {
+		return SessionClosed
+	}
+
+	y, m, d := t.Date()
+	kind := yearOf(y).days[dayKey(m, d)]
+	if kind == holiday {
+		return SessionClosed
+	}
+
+	closeAt, afterHoursCloseAt := 16*60, 20*60
+	if kind == earlyClose {
+		closeAt, afterHoursCloseAt = 13*60, 17*60
+	}
+
+	hour, min, _ := t.Clock()
+	switch minute := hour*60 + min; {
+	case minute < 4*60:
+		return SessionClosed
+	case minute < 9*60+30:
+		return SessionPreMarket
+	case minute < closeAt:
+		return SessionRegular
+	case minute < afterHoursCloseAt:
+		return SessionAfterHours
+	default:
+		return SessionClosed
+	}
+}
+
+// TradingDays returns the trading days overlapping [begin, end].
+func TradingDays(begin, end time.Time) []*Day {
+	var days []*Day
+	y, m, d := begin.In(Location).Date()
+	for date := time.Date(y, m, d, 0, 0, 0, 0, Location); !date.After(end); date = date.AddDate(0, 0, 1) {
+		if day, ok := TradingDay(date); ok {
+			days = append(days, day)
+		}
+	}
+
+	return days
+}
+
+func isWeekend(t time.Time) bool {
+	return t.Weekday() == time.Saturday || t.Weekday() == time.Sunday
+}
+
+type dayKind int
+
+const (
+	normal dayKind = iota
+	holiday
+	earlyClose
+)
+
+func dayKey(m time.Month, d int) int {
+	return int(m)*100 + d
+}
+
+// year holds the holidays and early closes of a year, by dayKey.
+type year struct {
+	year int
+	days map[int]dayKind
+}
+
+var (
+	// years caches each *year by year. A year is read-only once built,
+	// so lookups do not lock.
+	years sync.Map
+	// lastYear is the most recently used year, which avoids boxing
+	// the key of years for runs of records from the same year.
+	lastYear atomic.Pointer[year]
+)
+
+func yearOf(y int) *year {
+	if yr := lastYear.Load(); yr != nil && yr.year == y {
+		return yr
+	}
+
+	v, ok := years.Load(y)
+	if !ok {
+		v, _ = years.LoadOrStore(y, newYear(y))
+	}
+
+	yr := v.(*year)
+	lastYear.Store(yr)
+	return yr
+}
+
+// closures are unscheduled market closures.
+var closures = []time.Time{
+	time.Date(2001, 9, 11, 0, 0, 0, 0, time.UTC),
+	time.Date(2001, 9, 12, 0, 0, 0, 0, time.UTC),
+	time.Date(2001, 9, 13, 0, 0, 0, 0, time.UTC),
+	time.Date(2001, 9, 14, 0, 0, 0, 0, time.UTC),
+	time.Date(2004, 6, 11, 0, 0, 0, 0, time.UTC),
+	time.Date(2007, 1, 2, 0, 0, 0, 0, time.UTC),
+	time.Date(2012, 10, 29, 0, 0, 0, 0, time.UTC),
+	time.Date(2012, 10, 30, 0, 0, 0, 0, time.UTC),
+	time.Date(2018, 12, 5, 0, 0, 0, 0, time.UTC),
+	time.Date(2025, 1, 9, 0, 0, 0, 0, time.UTC),
+}
+
+func newYear(y int) *year {
+	yr := &year{year: y, days: map[int]dayKind{}}
+	set := func(t time.Time, kind dayKind) {
+		yr.days[dayKey(t.Month(), t.Day())] = kind
+	}
+	date := func(m time.Month, d int) time.Time {
+		return time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+	}
+
+	// New Year's Day is not observed on the preceding Friday
+	// when it falls on a Saturday.
+	if newYear := date(time.January, 1); newYear.Weekday() == time.Sunday {
+		set(newYear.AddDate(0, 0, 1), holiday)
+	} else {
+		set(newYear, holiday)
+	}
+
+	if y >= 1998 {
+		set(nthWeekday(y, time.January, time.Monday, 3), holiday)
+	}
+	set(nthWeekday(y, time.February, time.Monday, 3), holiday)
+	set(easter(y).AddDate(0, 0, -2), holiday)
+	set(nthWeekday(y, time.June, time.Monday, 1).AddDate(0, 0, -7), holiday)
+	if y >= 2022 {
+		set(observed(date(time.June, 19)), holiday)
+	}
+	set(observed(date(time.July, 4)), holiday)
+	set(nthWeekday(y, time.September, time.Monday, 1), holiday)
+	thanksgiving := nthWeekday(y, time.November, time.Thursday, 4)
+	set(thanksgiving, holiday)
+	set(observed(date(time.December, 25)), holiday)
+
+	for _, closure := range closures {
+		if closure.Year() == y {
+			set(closure, holiday)
+		}
+	}
+
+	// Early closes fall on the trading day before Independence Day and
+	// Christmas, if that is Monday to Thursday, and after Thanksgiving.
+	for _, t := range []time.Time{date(time.July, 3), date(time.December, 24)} {
+		if t.Weekday() >= time.Monday && t.Weekday() <= time.Thursday {
+			if _, ok := yr.days[dayKey(t.Month(), t.Day())]; !ok {
+				set(t, earlyClose)
+			}
+		}
+	}
+	set(thanksgiving.AddDate(0, 0, 1), earlyClose)
+
+	return yr
+}
+
+// observed returns the weekday on which a holiday on t is observed:
+// the preceding Friday for Saturday, or the following Monday for Sunday.
+func observed(t time.Time) time.Time {
+	switch t.Weekday() {
+	case time.Saturday:
+		return t.AddDate(0, 0, -1)
+	case time.Sunday:
+		return t.AddDate(0, 0, 1)
+	default:
+		return t
+	}
+}
+
+// nthWeekday returns the nth weekday of the month.
+func nthWeekday(y int, m time.Month, weekday time.Weekday, n int) time.Time {
+	t := time.Date(y, m, 1, 0, 0, 0, 0, time.UTC)
+	offset := (int(weekday) - int(t.Weekday()) + 7) % 7
+	return t.AddDate(0, 0, offset+7*(n-1))
+}
+
+// easter returns Easter Sunday of the year, using the anonymous
+// Gregorian algorithm.
+func easter(y int) time.Time {
+	a := y % 19
+	b, c := y/100, y%100
+	d, e := b/4, b%4
+	f := (b + 8) / 25
+	g := (b - f + 1) / 3
+	h := (19*a + b - d - g + 15) % 30
+	i, k := c/4, c%4
+	l := (32 + 2*e + 2*i - h - k) % 7
+	m := (a + 11*h + 22*l) / 451
+	month := (h + l - 7*m + 114) / 31
+	day := (h+l-7*m+114)%31 + 1
+	return time.Date(y, time.Month(month), day, 0, 0, 0, 0, time.UTC)
+}
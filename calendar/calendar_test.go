@@ -0,0 +1,140 @@
+package calendar
+
+import (
+	"testing"
+	"time"
+)
+
+func date(y int, m time.Month, d int) time.Time {
+	return time.Date(y, m, d, 12, 0, 0, 0, Location)
+}
+
+func TestHolidays(t *testing.T) {
+	holidays := []time.Time{
+		date(2022, time.January, 17),
+		date(2022, time.April, 15),
+		date(2022, time.June, 20),
+		date(2022, time.December, 26),
+		date(2023, time.January, 2),
+		date(2023, time.May, 29),
+		date(2023, time.June, 19),
+		date(2023, time.November, 23),
+		date(2024, time.March, 29),
+		date(2024, time.September, 2),
+		date(2021, time.December, 24),
+		date(2012, time.October, 29),
+		date(2025, time.January, 9),
+	}
+
+	for _, day := range holidays {
+		if !IsHoliday(day) || IsTradingDay(day) {
+			t.Errorf("Expected %v to be a holiday", day.Format("2006-01-02"))
+		}
+	}
+
+	tradingDays := []time.Time{
+		date(2021, time.June, 18),
+		date(2021, time.December, 31),
+		date(2022, time.January, 3),
+		date(2024, time.December, 24),
+	}
+
+	for _, day := range tradingDays {
+		if IsHoliday(day) || !IsTradingDay(day) {
+			t.Errorf("Expected %v to be a trading day", day.Format("2006-01-02"))
+		}
+	}
+
+	if weekend := date(2024, time.June, 15); IsHoliday(weekend) || IsTradingDay(weekend) {
+		t.Errorf("Expected %v to be a weekend", weekend.Format("2006-01-02"))
+	}
+}
+
+func TestEarlyClose(t *testing.T) {
+	tests := []struct {
+		date  time.Time
+		early bool
+	}{
+		{date(2023, time.July, 3), true},
+		{date(2023, time.November, 24), true},
+		{date(2024, time.December, 24), true},
+		{date(2025, time.July, 3), true},
+		{date(2022, time.July, 1), false},
+		{date(2020, time.July, 2), false},
+		{date(2024, time.December, 23), false},
+	}
+
+	for _, tt := range tests {
+		if got := IsEarlyClose(tt.date); got != tt.early {
+			t.Errorf("%v: expected early close %v, got %v", tt.date.Format("2006-01-02"), tt.early, got)
+		}
+	}
+}
+
+func TestSessionAt(t *testing.T) {
+	at := func(y int, m time.Month, d, hour, min int) time.Time {
+		return time.Date(y, m, d, hour, min, 0, 0, Location)
+	}
+
+	tests := []struct {
+		t        time.Time
+		expected Session
+	}{
+		{at(2024, time.March, 11, 3, 59), SessionClosed},
+		{at(2024, time.March, 11, 4, 0), SessionPreMarket},
+		{at(2024, time.March, 11, 9, 30), SessionRegular},
+		{at(2024, time.March, 11, 15, 59), SessionRegular},
+		{at(2024, time.March, 11, 16, 0), SessionAfterHours},
+		{at(2024, time.March, 11, 20, 0), SessionClosed},
+		{at(2024, time.November, 29, 12, 59), SessionRegular},
+		{at(2024, time.November, 29, 13, 0), SessionAfterHours},
+		{at(2024, time.November, 29, 17, 0), SessionClosed},
+		{at(2024, time.November, 28, 10, 0), SessionClosed},
+		{at(2024, time.March, 9, 10, 0), SessionClosed},
+		// 09:30 in New York is 13:30 UTC during DST.
+		{time.Date(2024, time.March, 11, 13, 30, 0, 0, time.UTC), SessionRegular},
+	}
+
+	for _, tt := range tests {
+		if got := SessionAt(tt.t); got != tt.expected {
+			t.Errorf("%v: expected %v, got %v", tt.t, tt.expected, got)
+		}
+	}
+}
+
+func TestSessionAtAllocs(t *testing.T) {
+	ts := time.Date(2024, time.March, 11, 13, 30, 0, 0, time.UTC)
+	SessionAt(ts)
+	if allocs := testing.AllocsPerRun(100, func() { SessionAt(ts) }); allocs != 0 {
+		t.Errorf("Expected no allocations, got %v", allocs)
+	}
+}
+
+func TestTradingDays(t *testing.T) {
+	days := TradingDays(date(2024, time.December, 20), date(2024, time.December, 27))
+	expected := []int{20, 23, 24, 26, 27}
+	if len(days) != len(expected) {
+		t.Fatalf("Expected %d trading days, got %d", len(expected), len(days))
+	}
+
+	for i, day := range days {
+		if day.Date.Day() != expected[i] {
+			t.Errorf("Expected December %d, got %v", expected[i], day.Date)
+		}
+	}
+}
+
+func TestSessionText(t *testing.T) {
+	for _, s := range []Session{SessionClosed, SessionPreMarket, SessionRegular, SessionAfterHours} {
+		text, _ := s.MarshalText()
+		var got Session
+		if err := got.UnmarshalText(text); err != nil || got != s {
+			t.Errorf("%v: round trip gave %v, %v", s, got, err)
+		}
+	}
+
+	var s Session
+	if err := s.UnmarshalText([]byte("lunch")); err == nil {
+		t.Errorf("Expected error, got %v", s)
+	}
+}
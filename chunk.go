@@ -0,0 +1,232 @@
+package activetick
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/timpalpant/go-activetick/calendar"
+)
+
+// ChunkOptions configures the concurrent chunked downloads of
+// PagingClient.GetBarDataChunked and GetTickDataChunked.
+type ChunkOptions struct {
+	// Window is the length of time fetched by each chunk.
+	// Defaults to one day.
+	Window time.Duration
+	// Workers is the number of chunks fetched concurrently.
+	// Defaults to 4.
+	Workers int
+	// TradingDaysOnly skips windows that do not overlap the extended
+	// hours (04:00 to 20:00 US Eastern) of any US equity trading day.
+	TradingDaysOnly bool
+}
+
+const (
+	defaultChunkWindow  = 24 * time.Hour
+	defaultChunkWorkers = 4
+)
+
+// chunk is the time range [begin, end) of a single window. The last
+// window also includes records at its end time, like the original request.
+type chunk struct {
+	begin, end time.Time
+	last       bool
+}
+
+func (c chunk) contains(t time.Time) bool {
+	return !t.Before(c.begin) && (t.Before(c.end) || (c.last && t.Equal(c.end)))
+}
+
+func splitChunks(begin, end time.Time, window time.Duration) []chunk {
+	var chunks []chunk
+	for b := begin; b.Before(end); b = b.Add(window) {
+		e := b.Add(window)
+		if !e.Before(end) {
+			e = end
+		}
+
+		chunks = append(chunks, chunk{b, e, e.Equal(end)})
+	}
+
+	if len(chunks) == 0 {
+		chunks = append(chunks, chunk{begin, end, true})
+	}
+
+	return chunks
+}
+
+// tradingChunks returns the chunks that overlap the extended hours
+// of a trading day.
+func tradingChunks(chunks []chunk) []chunk {
+	var trading []chunk
+	for _, c := range chunks {
+		for _, day := range calendar.TradingDays(c.begin, c.end) {
+			if day.PreMarketOpen.After(c.end) || !day.AfterHoursClose.After(c.begin) {
+				continue
+			}
+
+			trading = append(trading, c)
+			break
+		}
+	}
+
+	return trading
+}
+
+// runChunks calls fetch for each chunk using the given number of workers.
+// The first error cancels all remaining chunks and is returned.
+func runChunks(ctx context.Context, chunks []chunk, workers int, fetch func(context.Context, int, chunk) error) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	indices := make(chan int)
+	go func() {
+		defer close(indices)
+		for i := range chunks {
+			select {
+			case indices <- i:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	var wg sync.WaitGroup
+	var once sync.Once
+	var firstErr error
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indices {
+				if err := fetch(ctx, i, chunks[i]); err != nil {
+					once.Do(func() {
+						firstErr = err
+						cancel()
+					})
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	if firstErr != nil {
+		return firstErr
+	}
+
+	return ctx.Err()
+}
+
+func (opts ChunkOptions) withDefaults() ChunkOptions {
+	if opts.Window <= 0 {
+		opts.Window = defaultChunkWindow
+	}
+
+	if opts.Workers <= 0 {
+		opts.Workers = defaultChunkWorkers
+	}
+
+	return opts
+}
+
+// GetBarDataChunked is like GetBarDataContext, but splits the requested
+// time range into windows that are fetched (and paged) concurrently.
+// The records of all windows are returned in order.
+func (pc *PagingClient) GetBarDataChunked(ctx context.Context, req *BarDataRequest, opts ChunkOptions) (*BarDataResponse, error) {
+	opts = opts.withDefaults()
+	chunks := splitChunks(req.BeginTime, req.EndTime, opts.Window)
+	if opts.TradingDaysOnly {
+		chunks = tradingChunks(chunks)
+	}
+
+	results := make([][]*BarDataRecord, len(chunks))
+	err := runChunks(ctx, chunks, opts.Workers, func(ctx context.Context, i int, c chunk) error {
+		page, err := pc.GetBarDataContext(ctx, &BarDataRequest{
+			Symbol:          req.Symbol,
+			HistoryType:     req.HistoryType,
+			IntradayMinutes: req.IntradayMinutes,
+			BeginTime:       c.begin,
+			EndTime:         c.end,
+		})
+		if err != nil {
+			return err
+		}
+
+		for _, record := range page.Records {
+			if c.contains(record.Time) {
+				results[i] = append(results[i], record)
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &BarDataResponse{
+		Records: []*BarDataRecord{},
+	}
+
+	for _, records := range results {
+		resp.Records = append(resp.Records, records...)
+	}
+
+	return resp, nil
+}
+
+// GetTickDataChunked is like GetTickDataContext, but splits the requested
+// time range into windows that are fetched (and paged) concurrently.
+// The records of all windows are returned in order.
+func (pc *PagingClient) GetTickDataChunked(ctx context.Context, req *TickDataRequest, opts ChunkOptions) (*TickDataResponse, error) {
+	opts = opts.withDefaults()
+	chunks := splitChunks(req.BeginTime, req.EndTime, opts.Window)
+	if opts.TradingDaysOnly {
+		chunks = tradingChunks(chunks)
+	}
+
+	results := make([][]*TickRecord, len(chunks))
+	err := runChunks(ctx, chunks, opts.Workers, func(ctx context.Context, i int, c chunk) error {
+		it, err := pc.TickIterator(ctx, &TickDataRequest{
+			Symbol:    req.Symbol,
+			Trades:    req.Trades,
+			Quotes:    req.Quotes,
+			BeginTime: c.begin,
+			EndTime:   c.end,
+		})
+		if err != nil {
+			return err
+		}
+		defer it.Close()
+
+		// Ticks within the final second of the request are
+		// included in the last window.
+		if c.last {
+			c.end = c.end.Truncate(time.Second).Add(time.Second)
+			c.last = false
+		}
+
+		for it.Next() {
+			if record := it.Record(); c.contains(record.Time) {
+				results[i] = append(results[i], record)
+			}
+		}
+
+		return it.Err()
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &TickDataResponse{
+		Records: []*TickRecord{},
+	}
+
+	for _, records := range results {
+		resp.Records = append(resp.Records, records...)
+	}
+
+	return resp, nil
+}
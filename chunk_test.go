@@ -0,0 +1,30 @@
+package activetick
+
+import (
+	"testing"
+	"time"
+
+	"github.com/timpalpant/go-activetick/calendar"
+)
+
+func TestTradingChunks(t *testing.T) {
+	// Thanksgiving, the early close after it, a weekend and a Monday.
+	begin := time.Date(2024, 11, 28, 0, 0, 0, 0, calendar.Location)
+	chunks := tradingChunks(splitChunks(begin, begin.AddDate(0, 0, 5), 24*time.Hour))
+	expected := []int{29, 2}
+	if len(chunks) != len(expected) {
+		t.Fatalf("Expected %d chunks, got %v", len(expected), chunks)
+	}
+
+	for i, c := range chunks {
+		if c.begin.Day() != expected[i] {
+			t.Errorf("Chunk %d: expected day %d, got %v", i, expected[i], c.begin)
+		}
+	}
+
+	// Overnight windows between trading days are skipped too.
+	night := time.Date(2024, 12, 2, 20, 0, 0, 0, calendar.Location)
+	if chunks := tradingChunks(splitChunks(night, night.Add(7*time.Hour), time.Hour)); len(chunks) != 0 {
+		t.Errorf("Expected no chunks, got %v", chunks)
+	}
+}
@@ -1,13 +1,17 @@
 package activetick
 
 import (
+	"context"
 	"encoding/csv"
 	"fmt"
 	"io/ioutil"
 	"net/http"
 	"net/url"
 	"strconv"
+	"strings"
 	"time"
+
+	"github.com/timpalpant/go-activetick/calendar"
 )
 
 const (
@@ -15,26 +19,55 @@ const (
 )
 
 // Client provides methods to interact with the ActiveTick HTTP API.
-// TODO: Implement snapshot quotes (/quoteData) and streaming quotes
-// (/quoteStream).
 type Client struct {
-	client   *http.Client
-	endpoint string
+	client      *http.Client
+	endpoint    string
+	retry       *RetryPolicy
+	rateLimiter *rateLimiter
+	inFlight    chan struct{}
+	location    *time.Location
+}
+
+// ClientOption configures optional behavior of a Client.
+type ClientOption func(*Client)
+
+func NewClient(client *http.Client, endpoint string, opts ...ClientOption) *Client {
+	c := &Client{
+		client:   client,
+		endpoint: endpoint,
+		location: calendar.Location,
+	}
+
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	return c
 }
 
-func NewClient(client *http.Client, endpoint string) *Client {
-	return &Client{client, endpoint}
+// WithLocation sets the time zone of the ActiveTick HTTP server, in which
+// request times are sent and response times are interpreted. The default
+// is America/New_York.
+func WithLocation(loc *time.Location) ClientOption {
+	return func(c *Client) {
+		c.location = loc
+	}
 }
 
 func (c *Client) GetBarData(req *BarDataRequest) (*BarDataResponse, error) {
+	return c.GetBarDataContext(context.Background(), req)
+}
+
+// GetBarDataContext is like GetBarData, but the request is cancelled when ctx is done.
+func (c *Client) GetBarDataContext(ctx context.Context, req *BarDataRequest) (*BarDataResponse, error) {
 	values := url.Values{}
 	values.Set("symbol", req.Symbol)
 	values.Set("historyType", strconv.Itoa(int(req.HistoryType)))
 	values.Set("intradayMinutes", strconv.Itoa(req.IntradayMinutes))
-	values.Set("beginTime", req.BeginTime.Format(timeFormat))
-	values.Set("endTime", req.EndTime.Format(timeFormat))
+	values.Set("beginTime", req.BeginTime.In(c.location).Format(timeFormat))
+	values.Set("endTime", req.EndTime.In(c.location).Format(timeFormat))
 
-	result, err := c.getCSV("/barData", values)
+	result, err := c.getCSV(ctx, "/barData", values)
 	if err != nil {
 		return nil, err
 	}
@@ -43,10 +76,14 @@ func (c *Client) GetBarData(req *BarDataRequest) (*BarDataResponse, error) {
 		Records: make([]*BarDataRecord, 0, len(result)),
 	}
 
-	for _, row := range result {
-		record, err := parseBarData(row)
+	for i, row := range result {
+		record, err := parseBarData(row, c.location)
 		if err != nil {
-			return nil, err
+			return nil, &ParseError{Row: i + 1, Record: row, Err: err}
+		}
+
+		if req.HistoryType == HistoryTypeIntraday {
+			record.Session = calendar.SessionAt(record.Time)
 		}
 
 		resp.Records = append(resp.Records, record)
@@ -55,13 +92,13 @@ func (c *Client) GetBarData(req *BarDataRequest) (*BarDataResponse, error) {
 	return resp, err
 }
 
-func parseBarData(row []string) (*BarDataRecord, error) {
+func parseBarData(row []string, loc *time.Location) (*BarDataRecord, error) {
 	if len(row) != 6 {
 		return nil, fmt.Errorf("Expected %d rows, got %d: %v",
 			6, len(row), row)
 	}
 
-	t, err := time.Parse(timeFormat, row[0])
+	t, err := time.ParseInLocation(timeFormat, row[0], loc)
 	if err != nil {
 		return nil, err
 	}
@@ -102,6 +139,31 @@ func parseBarData(row []string) (*BarDataRecord, error) {
 }
 
 func (c *Client) GetTickData(req *TickDataRequest) (*TickDataResponse, error) {
+	return c.GetTickDataContext(context.Background(), req)
+}
+
+// GetTickDataContext is like GetTickData, but the request is cancelled when ctx is done.
+// Unlike TickDataIterator, a response that fails partway through is
+// retried from the beginning.
+func (c *Client) GetTickDataContext(ctx context.Context, req *TickDataRequest) (*TickDataResponse, error) {
+	values := tickDataValues(req, c.location)
+	var result *TickDataResponse
+	err := c.withRetry(ctx, func() error {
+		resp, err := c.doGet(ctx, "/tickData", values)
+		if err != nil {
+			return err
+		}
+
+		it := c.newTickIterator(resp.Body)
+		defer it.Close()
+		result, err = collectTicks(it)
+		return err
+	})
+
+	return result, err
+}
+
+func tickDataValues(req *TickDataRequest, loc *time.Location) url.Values {
 	values := url.Values{}
 	values.Set("symbol", req.Symbol)
 	tradesFlag := "0"
@@ -115,45 +177,27 @@ func (c *Client) GetTickData(req *TickDataRequest) (*TickDataResponse, error) {
 	}
 	values.Set("quotes", quotesFlag)
 	// NOTE: Milliseconds are not supported as suggested in the documentation.
-	values.Set("beginTime", req.BeginTime.Format(timeFormat))
-	values.Set("endTime", req.EndTime.Format(timeFormat))
-
-	result, err := c.getCSV("/tickData", values)
-	if err != nil {
-		return nil, err
-	}
-
-	resp := &TickDataResponse{
-		Records: make([]*TickRecord, 0, len(result)),
-	}
-
-	for _, row := range result {
-		record, err := parseTickData(row)
-		if err != nil {
-			return nil, err
-		}
-
-		resp.Records = append(resp.Records, record)
-	}
-
-	return resp, err
+	values.Set("beginTime", req.BeginTime.In(loc).Format(timeFormat))
+	values.Set("endTime", req.EndTime.In(loc).Format(timeFormat))
+	return values
 }
 
-func parseTickData(row []string) (*TickRecord, error) {
+func parseTickData(row []string, loc *time.Location) (*TickRecord, error) {
 	if len(row) < 9 {
 		return nil, fmt.Errorf("Expected %d rows, got %d: %v",
 			9, len(row), row)
 	}
 
 	tickType := TickType(row[0])
-	t, err := parseTime(row[1])
+	t, err := parseTime(row[1], loc)
 	if err != nil {
 		return nil, err
 	}
 
 	record := &TickRecord{
-		Type: tickType,
-		Time: t,
+		Type:    tickType,
+		Time:    t,
+		Session: calendar.SessionAt(t),
 	}
 
 	switch tickType {
@@ -220,8 +264,325 @@ func parseTickData(row []string) (*TickRecord, error) {
 	return record, nil
 }
 
-func parseTime(s string) (time.Time, error) {
-	t, err := time.Parse(timeFormat, s[:len(s)-3])
+func (c *Client) GetQuoteData(req *QuoteDataRequest) (*QuoteDataResponse, error) {
+	return c.GetQuoteDataContext(context.Background(), req)
+}
+
+// GetQuoteDataContext is like GetQuoteData, but the request is cancelled when ctx is done.
+func (c *Client) GetQuoteDataContext(ctx context.Context, req *QuoteDataRequest) (*QuoteDataResponse, error) {
+	fields := make([]string, len(req.QuoteFields))
+	for i, field := range req.QuoteFields {
+		fields[i] = strconv.Itoa(int(field))
+	}
+
+	values := url.Values{}
+	values.Set("symbol", strings.Join(req.Symbols, " "))
+	values.Set("field", strings.Join(fields, " "))
+
+	result, err := c.getCSV(ctx, "/quoteData", values)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &QuoteDataResponse{
+		Records: make([]*QuoteSnapshotRecord, 0, len(result)),
+	}
+
+	for i, row := range result {
+		record, err := parseQuoteData(row, c.location)
+		if err != nil {
+			return nil, &ParseError{Row: i + 1, Record: row, Err: err}
+		}
+
+		resp.Records = append(resp.Records, record)
+	}
+
+	return resp, nil
+}
+
+// Each row of a /quoteData response is the symbol and its status,
+// followed by a (field, status, data type, value) group for each
+// requested field.
+func parseQuoteData(row []string, loc *time.Location) (*QuoteSnapshotRecord, error) {
+	if len(row) < 2 || (len(row)-2)%4 != 0 {
+		return nil, fmt.Errorf("Invalid quote data row: %v", row)
+	}
+
+	status, err := strconv.Atoi(row[1])
+	if err != nil {
+		return nil, err
+	}
+
+	record := &QuoteSnapshotRecord{
+		Symbol: row[0],
+		Status: SymbolStatus(status),
+	}
+
+	for i := 2; i < len(row); i += 4 {
+		field, err := strconv.Atoi(row[i])
+		if err != nil {
+			return nil, err
+		}
+
+		fieldStatus, err := strconv.Atoi(row[i+1])
+		if err != nil {
+			return nil, err
+		}
+
+		if QuoteFieldStatus(fieldStatus) != QuoteFieldStatusSuccess {
+			continue
+		}
+
+		dataType, err := strconv.Atoi(row[i+2])
+		if err != nil {
+			return nil, err
+		}
+
+		value, err := parseQuoteValue(DataItemType(dataType), row[i+3], loc)
+		if err != nil {
+			return nil, err
+		}
+
+		if err := setQuoteField(record, QuoteField(field), value); err != nil {
+			return nil, err
+		}
+	}
+
+	return record, nil
+}
+
+// parseQuoteValue decodes a single /quoteData value according to its
+// data type. Integers are returned as int64, prices and doubles as
+// float64, date times as time.Time and everything else as string.
+func parseQuoteValue(dataType DataItemType, s string, loc *time.Location) (interface{}, error) {
+	switch dataType {
+	case DataByte, DataByteArray, DataString, DataUnicodeString:
+		return s, nil
+	case DataUInteger32, DataUInteger64, DataInteger32, DataInteger64:
+		return strconv.ParseInt(s, 10, 64)
+	case DataPrice, DataDouble:
+		return strconv.ParseFloat(s, 64)
+	case DataDateTime:
+		return parseTime(s, loc)
+	default:
+		return nil, fmt.Errorf("Unknown data type: %v", dataType)
+	}
+}
+
+func setQuoteField(record *QuoteSnapshotRecord, field QuoteField, value interface{}) error {
+	var err error
+	switch field {
+	case QuoteFieldSymbol:
+		record.Symbol, err = quoteString(value)
+	case QuoteFieldOpenPrice:
+		record.OpenPrice, err = quoteFloat(value)
+	case QuoteFieldPreviousClosePrice:
+		record.PreviousClosePrice, err = quoteFloat(value)
+	case QuoteFieldClosePrice:
+		record.ClosePrice, err = quoteFloat(value)
+	case QuoteFieldLastPrice:
+		record.LastPrice, err = quoteFloat(value)
+	case QuoteFieldBidPrice:
+		record.BidPrice, err = quoteFloat(value)
+	case QuoteFieldAskPrice:
+		record.AskPrice, err = quoteFloat(value)
+	case QuoteFieldHighPrice:
+		record.HighPrice, err = quoteFloat(value)
+	case QuoteFieldLowPrice:
+		record.LowPrice, err = quoteFloat(value)
+	case QuoteFieldDayHighPrice:
+		record.DayHighPrice, err = quoteFloat(value)
+	case QuoteFieldDayLowPrice:
+		record.DayLowPrice, err = quoteFloat(value)
+	case QuoteFieldPreMarketOpenPrice:
+		record.PreMarketOpenPrice, err = quoteFloat(value)
+	case QuoteFieldExtendedHoursLastPrice:
+		record.ExtendedHoursLastPrice, err = quoteFloat(value)
+	case QuoteFieldAfterMarketClosePrice:
+		record.AfterMarketClosePrice, err = quoteFloat(value)
+	case QuoteFieldBidExchange:
+		var s string
+		s, err = quoteString(value)
+		record.BidExchange = Exchange(s)
+	case QuoteFieldAskExchange:
+		var s string
+		s, err = quoteString(value)
+		record.AskExchange = Exchange(s)
+	case QuoteFieldLastExchange:
+		var s string
+		s, err = quoteString(value)
+		record.LastExchange = Exchange(s)
+	case QuoteFieldLastCondition:
+		record.LastCondition, err = quoteInt(value)
+	case QuoteFieldQuoteCondition:
+		record.QuoteCondition, err = quoteInt(value)
+	case QuoteFieldLastTradeDateTime:
+		record.LastTradeTime, err = quoteTime(value)
+	case QuoteFieldLastQuoteDateTime:
+		record.LastQuoteTime, err = quoteTime(value)
+	case QuoteFieldDayHighDateTime:
+		record.DayHighTime, err = quoteTime(value)
+	case QuoteFieldDayLowDateTime:
+		record.DayLowTime, err = quoteTime(value)
+	case QuoteFieldLastSize:
+		record.LastSize, err = quoteInt(value)
+	case QuoteFieldBidSize:
+		record.BidSize, err = quoteInt(value)
+	case QuoteFieldAskSize:
+		record.AskSize, err = quoteInt(value)
+	case QuoteFieldVolume:
+		record.Volume, err = quoteInt(value)
+	case QuoteFieldPreMarketVolume:
+		record.PreMarketVolume, err = quoteInt(value)
+	case QuoteFieldAfterMarketVolume:
+		record.AfterMarketVolume, err = quoteInt(value)
+	case QuoteFieldTradeCount:
+		record.TradeCount, err = quoteInt(value)
+	case QuoteFieldPreMarketTradeCount:
+		record.PreMarketTradeCount, err = quoteInt(value)
+	case QuoteFieldAfterMarketTradeCount:
+		record.AfterMarketTradeCount, err = quoteInt(value)
+	case QuoteFieldFundamentalEquityName:
+		record.FundamentalEquityName, err = quoteString(value)
+	case QuoteFieldFundamentalEquityPrimaryExchange:
+		var s string
+		s, err = quoteString(value)
+		record.FundamentalEquityPrimaryExchange = Exchange(s)
+	default:
+		return fmt.Errorf("Unknown quote field: %v", field)
+	}
+
+	if err != nil {
+		return fmt.Errorf("Quote field %v: %v", field, err)
+	}
+
+	return nil
+}
+
+func quoteString(value interface{}) (string, error) {
+	switch v := value.(type) {
+	case string:
+		return v, nil
+	default:
+		return "", fmt.Errorf("Expected string, got %T", value)
+	}
+}
+
+func quoteFloat(value interface{}) (float64, error) {
+	switch v := value.(type) {
+	case float64:
+		return v, nil
+	case int64:
+		return float64(v), nil
+	default:
+		return 0, fmt.Errorf("Expected number, got %T", value)
+	}
+}
+
+// Conditions may be encoded as single bytes, so numeric strings
+// are also accepted for integer fields.
+func quoteInt(value interface{}) (int, error) {
+	switch v := value.(type) {
+	case int64:
+		return int(v), nil
+	case string:
+		return strconv.Atoi(v)
+	default:
+		return 0, fmt.Errorf("Expected integer, got %T", value)
+	}
+}
+
+func quoteTime(value interface{}) (time.Time, error) {
+	switch v := value.(type) {
+	case time.Time:
+		return v, nil
+	default:
+		return time.Time{}, fmt.Errorf("Expected date time, got %T", value)
+	}
+}
+
+func (c *Client) GetOptionChain(req *OptionChainRequest) (*OptionChainResponse, error) {
+	return c.GetOptionChainContext(context.Background(), req)
+}
+
+// GetOptionChainContext is like GetOptionChain, but the request is cancelled when ctx is done.
+func (c *Client) GetOptionChainContext(ctx context.Context, req *OptionChainRequest) (*OptionChainResponse, error) {
+	values := url.Values{}
+	values.Set("symbol", req.Symbol)
+
+	result, err := c.getCSV(ctx, "/optionChain", values)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &OptionChainResponse{
+		Records: make([]*OptionContract, 0, len(result)),
+	}
+
+	for i, row := range result {
+		for _, symbol := range row {
+			if symbol == "" {
+				continue
+			}
+
+			record, err := parseOptionSymbol(symbol)
+			if err != nil {
+				return nil, &ParseError{Row: i + 1, Record: row, Err: err}
+			}
+
+			resp.Records = append(resp.Records, record)
+		}
+	}
+
+	return resp, nil
+}
+
+// Option symbols follow the OCC format: the underlying root, followed by
+// the expiration date (YYMMDD), C or P, and the strike price multiplied
+// by 1000 as 8 digits. ActiveTick prefixes option symbols with a period.
+func parseOptionSymbol(symbol string) (*OptionContract, error) {
+	s := strings.TrimPrefix(symbol, ".")
+	if len(s) < 16 {
+		return nil, fmt.Errorf("Invalid option symbol: %q", symbol)
+	}
+
+	tail := s[len(s)-15:]
+	underlying := strings.TrimSpace(s[:len(s)-15])
+	if underlying == "" {
+		return nil, fmt.Errorf("Invalid option symbol: %q", symbol)
+	}
+
+	expiration, err := time.ParseInLocation("060102", tail[:6], calendar.Location)
+	if err != nil {
+		return nil, err
+	}
+
+	optionType := OptionType(tail[6:7])
+	if optionType != OptionTypeCall && optionType != OptionTypePut {
+		return nil, fmt.Errorf("Invalid option type in symbol: %q", symbol)
+	}
+
+	strike, err := strconv.ParseInt(tail[7:], 10, 64)
+	if err != nil {
+		return nil, err
+	}
+
+	return &OptionContract{
+		Symbol:     symbol,
+		Underlying: underlying,
+		Expiration: expiration,
+		Type:       optionType,
+		Strike:     float64(strike) / 1000,
+	}, nil
+}
+
+// parseTime parses a time with milliseconds, as sent by the server in loc.
+func parseTime(s string, loc *time.Location) (time.Time, error) {
+	if len(s) != len(timeFormat)+3 {
+		return time.Time{}, fmt.Errorf("Invalid time: %q", s)
+	}
+
+	t, err := time.ParseInLocation(timeFormat, s[:len(s)-3], loc)
 	if err != nil {
 		return t, err
 	}
@@ -234,24 +595,75 @@ func parseTime(s string) (time.Time, error) {
 	return t.Add(time.Duration(ms) * time.Millisecond), nil
 }
 
-func (c *Client) getCSV(route string, values url.Values) ([][]string, error) {
+// getCSV fetches and reads the entire response for the given route.
+// Failures while reading the response are also retried.
+func (c *Client) getCSV(ctx context.Context, route string, values url.Values) ([][]string, error) {
+	var result [][]string
+	err := c.withRetry(ctx, func() error {
+		resp, err := c.doGet(ctx, route, values)
+		if err != nil {
+			return err
+		}
+		defer resp.Body.Close()
+
+		reader := csv.NewReader(resp.Body)
+		// Rows of a /quoteData response vary in length with the
+		// status of each symbol.
+		reader.FieldsPerRecord = -1
+		result, err = reader.ReadAll()
+		return err
+	})
+
+	return result, err
+}
+
+// get issues a GET request for the given route, retrying according to
+// the client's retry policy. The caller is responsible for closing the
+// body of the returned response.
+func (c *Client) get(ctx context.Context, route string, values url.Values) (*http.Response, error) {
+	var resp *http.Response
+	err := c.withRetry(ctx, func() error {
+		var err error
+		resp, err = c.doGet(ctx, route, values)
+		return err
+	})
+
+	return resp, err
+}
+
+func (c *Client) doGet(ctx context.Context, route string, values url.Values) (*http.Response, error) {
 	url := c.endpoint + route
 	params := values.Encode()
 	if params != "" {
 		url = url + "?" + params
 	}
 
-	resp, err := c.client.Get(url)
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	release, err := c.acquire(ctx)
 	if err != nil {
 		return nil, err
 	}
-	defer resp.Body.Close()
 
+	resp, err := c.client.Do(req.WithContext(ctx))
+	if err != nil {
+		release()
+		return nil, err
+	}
+
+	resp.Body = &releasingBody{resp.Body, release}
 	if resp.StatusCode != http.StatusOK {
+		defer resp.Body.Close()
 		body, _ := ioutil.ReadAll(resp.Body)
-		return nil, fmt.Errorf("%v: %v", resp.Status, string(body))
+		return nil, &APIError{
+			StatusCode: resp.StatusCode,
+			Status:     resp.Status,
+			Body:       string(body),
+		}
 	}
 
-	reader := csv.NewReader(resp.Body)
-	return reader.ReadAll()
+	return resp, nil
 }
@@ -1,10 +1,18 @@
 package activetick
 
 import (
+	"context"
 	"encoding/csv"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"path/filepath"
 	"testing"
+	"time"
+
+	"github.com/timpalpant/go-activetick/calendar"
 )
 
 func loadCSVData(filename string) ([][]string, error) {
@@ -15,6 +23,7 @@ func loadCSVData(filename string) ([][]string, error) {
 	defer f.Close()
 
 	reader := csv.NewReader(f)
+	reader.FieldsPerRecord = -1
 	return reader.ReadAll()
 }
 
@@ -25,7 +34,7 @@ func TestParseBarData(t *testing.T) {
 	}
 
 	for _, row := range rows {
-		_, err := parseBarData(row)
+		_, err := parseBarData(row, calendar.Location)
 		if err != nil {
 			t.Error(err)
 		}
@@ -39,9 +48,137 @@ func TestParseTickData(t *testing.T) {
 	}
 
 	for _, row := range rows {
-		_, err := parseTickData(row)
+		_, err := parseTickData(row, calendar.Location)
+		if err != nil {
+			t.Error(err)
+		}
+	}
+}
+
+func TestParseQuoteData(t *testing.T) {
+	rows, err := loadCSVData("quoteDataResponse.csv")
+	if err != nil {
+		t.Error(err)
+	}
+
+	for _, row := range rows {
+		_, err := parseQuoteData(row, calendar.Location)
+		if err != nil {
+			t.Error(err)
+		}
+	}
+}
+
+func TestParseOptionSymbol(t *testing.T) {
+	rows, err := loadCSVData("optionChainResponse.csv")
+	if err != nil {
+		t.Error(err)
+	}
+
+	for _, row := range rows {
+		_, err := parseOptionSymbol(row[0])
 		if err != nil {
 			t.Error(err)
 		}
 	}
+
+	record, err := parseOptionSymbol(".AAPL  121019C00617500")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if record.Underlying != "AAPL" || record.Type != OptionTypeCall || record.Strike != 617.5 {
+		t.Errorf("Unexpected option contract: %+v", record)
+	}
+
+	if !record.Expiration.Equal(time.Date(2012, 10, 19, 0, 0, 0, 0, calendar.Location)) {
+		t.Errorf("Unexpected expiration: %v", record.Expiration)
+	}
+}
+
+func TestGetBarDataContextCancelled(t *testing.T) {
+	done := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-done:
+		}
+	}))
+	defer server.Close()
+	defer close(done)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	client := NewPagingClient(NewClient(server.Client(), server.URL))
+	_, err := client.GetBarDataContext(ctx, &BarDataRequest{Symbol: "SPY"})
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected context deadline to be exceeded, got %v", err)
+	}
+}
+
+func TestDailyBarsHaveNoSession(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintln(w, "20101101093000,26.880000,26.900000,26.860000,26.890000,1175094")
+	}))
+	defer server.Close()
+
+	client := NewClient(server.Client(), server.URL)
+	for _, historyType := range []HistoryType{HistoryTypeDaily, HistoryTypeWeekly} {
+		resp, err := client.GetBarData(&BarDataRequest{Symbol: "SPY", HistoryType: historyType})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if session := resp.Records[0].Session; session != calendar.SessionClosed {
+			t.Errorf("Expected no session for %v bars, got %v", historyType, session)
+		}
+	}
+}
+
+func TestClientLocation(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if begin := r.URL.Query().Get("beginTime"); begin != "20101101093000" {
+			t.Errorf("Expected beginTime in New York, got %v", begin)
+		}
+
+		fmt.Fprintln(w, "20101101093000,26.880000,26.900000,26.860000,26.890000,1175094")
+	}))
+	defer server.Close()
+
+	// 09:30 in New York is 13:30 UTC during DST, and 14:30 UTC after it ends.
+	tests := []struct {
+		begin    time.Time
+		expected time.Time
+	}{
+		{time.Date(2010, 11, 1, 13, 30, 0, 0, time.UTC), time.Date(2010, 11, 1, 13, 30, 0, 0, time.UTC)},
+		{time.Date(2010, 11, 1, 9, 30, 0, 0, calendar.Location), time.Date(2010, 11, 1, 13, 30, 0, 0, time.UTC)},
+	}
+
+	client := NewClient(server.Client(), server.URL)
+	for _, tt := range tests {
+		resp, err := client.GetBarData(&BarDataRequest{Symbol: "SPY", BeginTime: tt.begin, EndTime: tt.begin})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		record := resp.Records[0]
+		if !record.Time.Equal(tt.expected) || record.Time.Location() != calendar.Location {
+			t.Errorf("Expected %v in New York, got %v", tt.expected, record.Time)
+		}
+
+		if record.Session != calendar.SessionRegular {
+			t.Errorf("Expected regular session, got %v", record.Session)
+		}
+	}
+
+	tick, err := parseTickData([]string{"T", "20101108093000123", "1", "1", "Q", "0", "0", "0", "0"}, calendar.Location)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := time.Date(2010, 11, 8, 14, 30, 0, 123e6, time.UTC)
+	if !tick.Time.Equal(expected) {
+		t.Errorf("Expected %v, got %v", expected, tick.Time)
+	}
 }
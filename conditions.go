@@ -0,0 +1,117 @@
+package activetick
+
+// TradeConditions holds the (up to four) conditions of a trade.
+// Unused slots are TradeConditionRegular.
+type TradeConditions [4]TradeCondition
+
+// conditionRules describes which consolidated statistics a trade with
+// a given condition updates, following the CTA and UTP eligibility rules.
+type conditionRules struct {
+	last    bool
+	highLow bool
+	volume  bool
+}
+
+var tradeConditionRules = map[TradeCondition]conditionRules{
+	TradeConditionRegular:                       {true, true, true},
+	TradeConditionAcquisition:                   {true, true, true},
+	TradeConditionAveragePrice:                  {false, false, true},
+	TradeConditionAutomaticExecution:            {true, true, true},
+	TradeConditionBunched:                       {true, true, true},
+	TradeConditionBunchSold:                     {false, true, true},
+	TradeConditionCAPElection:                   {true, true, true},
+	TradeConditionCash:                          {false, false, true},
+	TradeConditionClosing:                       {true, true, true},
+	TradeConditionCross:                         {true, true, true},
+	TradeConditionDerivativelyPriced:            {false, true, true},
+	TradeConditionDistribution:                  {true, true, true},
+	TradeConditionFormT:                         {false, false, true},
+	TradeConditionFormTOutOfSequence:            {false, false, true},
+	TradeConditionInterMarketSweep:              {true, true, true},
+	TradeConditionMarketCenterOfficialClose:     {false, false, false},
+	TradeConditionMarketCenterOfficialOpen:      {false, false, false},
+	TradeConditionMarketCenterOpening:           {true, true, true},
+	TradeConditionMarketCenterReOpenning:        {true, true, true},
+	TradeConditionMarketCenterClosing:           {true, true, true},
+	TradeConditionNextDay:                       {false, false, true},
+	TradeConditionPriceVariation:                {false, false, true},
+	TradeConditionPriorReferencePrice:           {false, true, true},
+	TradeConditionRule155Amex:                   {true, true, true},
+	TradeConditionRule127Nyse:                   {true, true, true},
+	TradeConditionOpening:                       {true, true, true},
+	TradeConditionOpened:                        {false, true, true},
+	TradeConditionRegularStoppedStock:           {true, true, true},
+	TradeConditionReOpening:                     {true, true, true},
+	TradeConditionSeller:                        {false, false, true},
+	TradeConditionSoldLast:                      {true, true, true},
+	TradeConditionSoldLastStoppedStock:          {true, true, true},
+	TradeConditionSoldOutOfSequence:             {false, true, true},
+	TradeConditionSoldOutOfSequenceStoppedStock: {false, true, true},
+	TradeConditionSplit:                         {true, true, true},
+	TradeConditionStockOption:                   {true, true, true},
+	TradeConditionYellowFlag:                    {true, true, true},
+}
+
+// rules returns the eligibility of c. Unknown conditions
+// are treated like regular trades.
+func (c TradeCondition) rules() conditionRules {
+	if r, ok := tradeConditionRules[c]; ok {
+		return r
+	}
+
+	return conditionRules{true, true, true}
+}
+
+// UpdatesLast reports whether a trade with this condition
+// updates the consolidated last price.
+func (c TradeCondition) UpdatesLast() bool {
+	return c.rules().last
+}
+
+// UpdatesHighLow reports whether a trade with this condition
+// updates the consolidated high and low prices.
+func (c TradeCondition) UpdatesHighLow() bool {
+	return c.rules().highLow
+}
+
+// UpdatesVolume reports whether a trade with this condition
+// is included in consolidated volume.
+func (c TradeCondition) UpdatesVolume() bool {
+	return c.rules().volume
+}
+
+// UpdatesLast reports whether a trade with these conditions updates the
+// consolidated last price, i.e. whether all of its conditions allow it.
+func (cs TradeConditions) UpdatesLast() bool {
+	for _, c := range cs {
+		if !c.UpdatesLast() {
+			return false
+		}
+	}
+
+	return true
+}
+
+// UpdatesHighLow reports whether a trade with these conditions updates
+// the consolidated high and low prices.
+func (cs TradeConditions) UpdatesHighLow() bool {
+	for _, c := range cs {
+		if !c.UpdatesHighLow() {
+			return false
+		}
+	}
+
+	return true
+}
+
+// UpdatesVolume reports whether a trade with these conditions
+// is included in consolidated volume.
+func (cs TradeConditions) UpdatesVolume() bool {
+	for _, c := range cs {
+		if !c.UpdatesVolume() {
+			return false
+		}
+	}
+
+	return true
+}
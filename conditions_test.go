@@ -0,0 +1,35 @@
+package activetick
+
+import (
+	"testing"
+)
+
+func TestTradeConditionsEligibility(t *testing.T) {
+	tests := []struct {
+		conditions TradeConditions
+		last       bool
+		highLow    bool
+		volume     bool
+	}{
+		{TradeConditions{}, true, true, true},
+		{TradeConditions{TradeConditionInterMarketSweep}, true, true, true},
+		{TradeConditions{0, TradeConditionFormT}, false, false, true},
+		{TradeConditions{TradeConditionSoldOutOfSequence}, false, true, true},
+		{TradeConditions{TradeConditionInterMarketSweep, TradeConditionAveragePrice}, false, false, true},
+		{TradeConditions{TradeConditionMarketCenterOfficialClose}, false, false, false},
+	}
+
+	for _, tt := range tests {
+		if got := tt.conditions.UpdatesLast(); got != tt.last {
+			t.Errorf("%v: expected UpdatesLast %v, got %v", tt.conditions, tt.last, got)
+		}
+
+		if got := tt.conditions.UpdatesHighLow(); got != tt.highLow {
+			t.Errorf("%v: expected UpdatesHighLow %v, got %v", tt.conditions, tt.highLow, got)
+		}
+
+		if got := tt.conditions.UpdatesVolume(); got != tt.volume {
+			t.Errorf("%v: expected UpdatesVolume %v, got %v", tt.conditions, tt.volume, got)
+		}
+	}
+}
@@ -0,0 +1,357 @@
+package activetick
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Each enum type is rendered by name, both by String and as text
+// (e.g. in JSON). Values without a name are rendered as Type(N), which
+// UnmarshalText accepts along with names and bare numbers.
+
+var symbolStatuses = newEnum("SymbolStatus", map[int]string{
+	int(SymbolStatusSuccess):      "Success",
+	int(SymbolStatusInvalid):      "Invalid",
+	int(SymbolStatusUnavailable):  "Unavailable",
+	int(SymbolStatusNoPermission): "NoPermission",
+})
+
+var quoteFieldStatuses = newEnum("QuoteFieldStatus", map[int]string{
+	int(QuoteFieldStatusSuccess):     "Success",
+	int(QuoteFieldStatusInvalid):     "Invalid",
+	int(QuoteFieldStatusUnavailable): "Unavailable",
+	int(QuoteFieldStatusDenied):      "Denied",
+})
+
+var quoteFields = newEnum("QuoteField", map[int]string{
+	int(QuoteFieldSymbol):                           "Symbol",
+	int(QuoteFieldOpenPrice):                        "OpenPrice",
+	int(QuoteFieldPreviousClosePrice):               "PreviousClosePrice",
+	int(QuoteFieldClosePrice):                       "ClosePrice",
+	int(QuoteFieldLastPrice):                        "LastPrice",
+	int(QuoteFieldBidPrice):                         "BidPrice",
+	int(QuoteFieldAskPrice):                         "AskPrice",
+	int(QuoteFieldHighPrice):                        "HighPrice",
+	int(QuoteFieldLowPrice):                         "LowPrice",
+	int(QuoteFieldDayHighPrice):                     "DayHighPrice",
+	int(QuoteFieldDayLowPrice):                      "DayLowPrice",
+	int(QuoteFieldPreMarketOpenPrice):               "PreMarketOpenPrice",
+	int(QuoteFieldExtendedHoursLastPrice):           "ExtendedHoursLastPrice",
+	int(QuoteFieldAfterMarketClosePrice):            "AfterMarketClosePrice",
+	int(QuoteFieldBidExchange):                      "BidExchange",
+	int(QuoteFieldAskExchange):                      "AskExchange",
+	int(QuoteFieldLastExchange):                     "LastExchange",
+	int(QuoteFieldLastCondition):                    "LastCondition",
+	int(QuoteFieldQuoteCondition):                   "QuoteCondition",
+	int(QuoteFieldLastTradeDateTime):                "LastTradeDateTime",
+	int(QuoteFieldLastQuoteDateTime):                "LastQuoteDateTime",
+	int(QuoteFieldDayHighDateTime):                  "DayHighDateTime",
+	int(QuoteFieldDayLowDateTime):                   "DayLowDateTime",
+	int(QuoteFieldLastSize):                         "LastSize",
+	int(QuoteFieldBidSize):                          "BidSize",
+	int(QuoteFieldAskSize):                          "AskSize",
+	int(QuoteFieldVolume):                           "Volume",
+	int(QuoteFieldPreMarketVolume):                  "PreMarketVolume",
+	int(QuoteFieldAfterMarketVolume):                "AfterMarketVolume",
+	int(QuoteFieldTradeCount):                       "TradeCount",
+	int(QuoteFieldPreMarketTradeCount):              "PreMarketTradeCount",
+	int(QuoteFieldAfterMarketTradeCount):            "AfterMarketTradeCount",
+	int(QuoteFieldFundamentalEquityName):            "FundamentalEquityName",
+	int(QuoteFieldFundamentalEquityPrimaryExchange): "FundamentalEquityPrimaryExchange",
+})
+
+var dataItemTypes = newEnum("DataItemType", map[int]string{
+	int(DataByte):          "Byte",
+	int(DataByteArray):     "ByteArray",
+	int(DataUInteger32):    "UInteger32",
+	int(DataUInteger64):    "UInteger64",
+	int(DataInteger32):     "Integer32",
+	int(DataInteger64):     "Integer64",
+	int(DataPrice):         "Price",
+	int(DataString):        "String",
+	int(DataUnicodeString): "UnicodeString",
+	int(DataDateTime):      "DateTime",
+	int(DataDouble):        "Double",
+})
+
+var historyTypes = newEnum("HistoryType", map[int]string{
+	int(HistoryTypeIntraday): "Intraday",
+	int(HistoryTypeDaily):    "Daily",
+	int(HistoryTypeWeekly):   "Weekly",
+})
+
+var tradeConditions = newEnum("TradeCondition", map[int]string{
+	int(TradeConditionRegular):                       "Regular",
+	int(TradeConditionAcquisition):                   "Acquisition",
+	int(TradeConditionAveragePrice):                  "AveragePrice",
+	int(TradeConditionAutomaticExecution):            "AutomaticExecution",
+	int(TradeConditionBunched):                       "Bunched",
+	int(TradeConditionBunchSold):                     "BunchSold",
+	int(TradeConditionCAPElection):                   "CAPElection",
+	int(TradeConditionCash):                          "Cash",
+	int(TradeConditionClosing):                       "Closing",
+	int(TradeConditionCross):                         "Cross",
+	int(TradeConditionDerivativelyPriced):            "DerivativelyPriced",
+	int(TradeConditionDistribution):                  "Distribution",
+	int(TradeConditionFormT):                         "FormT",
+	int(TradeConditionFormTOutOfSequence):            "FormTOutOfSequence",
+	int(TradeConditionInterMarketSweep):              "InterMarketSweep",
+	int(TradeConditionMarketCenterOfficialClose):     "MarketCenterOfficialClose",
+	int(TradeConditionMarketCenterOfficialOpen):      "MarketCenterOfficialOpen",
+	int(TradeConditionMarketCenterOpening):           "MarketCenterOpening",
+	int(TradeConditionMarketCenterReOpenning):        "MarketCenterReOpenning",
+	int(TradeConditionMarketCenterClosing):           "MarketCenterClosing",
+	int(TradeConditionNextDay):                       "NextDay",
+	int(TradeConditionPriceVariation):                "PriceVariation",
+	int(TradeConditionPriorReferencePrice):           "PriorReferencePrice",
+	int(TradeConditionRule155Amex):                   "Rule155Amex",
+	int(TradeConditionRule127Nyse):                   "Rule127Nyse",
+	int(TradeConditionOpening):                       "Opening",
+	int(TradeConditionOpened):                        "Opened",
+	int(TradeConditionRegularStoppedStock):           "RegularStoppedStock",
+	int(TradeConditionReOpening):                     "ReOpening",
+	int(TradeConditionSeller):                        "Seller",
+	int(TradeConditionSoldLast):                      "SoldLast",
+	int(TradeConditionSoldLastStoppedStock):          "SoldLastStoppedStock",
+	int(TradeConditionSoldOutOfSequence):             "SoldOutOfSequence",
+	int(TradeConditionSoldOutOfSequenceStoppedStock): "SoldOutOfSequenceStoppedStock",
+	int(TradeConditionSplit):                         "Split",
+	int(TradeConditionStockOption):                   "StockOption",
+	int(TradeConditionYellowFlag):                    "YellowFlag",
+})
+
+// tradeFlagNames is ordered by bit so that combinations render consistently.
+var tradeFlagNames = []struct {
+	flag TradeFlag
+	name string
+}{
+	{TradeFlagRegularMarketLastPrice, "RegularMarketLastPrice"},
+	{TradeFlagRegularMarketVolume, "RegularMarketVolume"},
+	{TradeFlagHighPrice, "HighPrice"},
+	{TradeFlagLowPrice, "LowPrice"},
+	{TradeFlagDayHighPrice, "DayHighPrice"},
+	{TradeFlagDayLowPrice, "DayLowPrice"},
+	{TradeFlagExtendedMarketLastPrice, "ExtendedMarketLastPrice"},
+	{TradeFlagPreMarketVolume, "PreMarketVolume"},
+	{TradeFlagAfterMarketVolume, "AfterMarketVolume"},
+	{TradeFlagPreMarketOpenPrice, "PreMarketOpenPrice"},
+	{TradeFlagOpenPrice, "OpenPrice"},
+}
+
+var tickTypeNames = map[TickType]string{
+	TickTypeQuote: "Quote",
+	TickTypeTrade: "Trade",
+}
+
+// enum maps the values of an enum type to their names and back.
+type enum struct {
+	typ    string
+	names  map[int]string
+	values map[string]int
+}
+
+func newEnum(typ string, names map[int]string) *enum {
+	values := make(map[string]int, len(names))
+	for v, name := range names {
+		values[name] = v
+	}
+
+	return &enum{typ, names, values}
+}
+
+func (e *enum) format(v int) string {
+	if name, ok := e.names[v]; ok {
+		return name
+	}
+
+	return e.typ + "(" + strconv.Itoa(v) + ")"
+}
+
+func (e *enum) parse(text []byte) (int, error) {
+	s := string(text)
+	if v, ok := e.values[s]; ok {
+		return v, nil
+	}
+
+	if strings.HasPrefix(s, e.typ+"(") && strings.HasSuffix(s, ")") {
+		s = s[len(e.typ)+1 : len(s)-1]
+	}
+
+	v, err := strconv.Atoi(s)
+	if err != nil {
+		return 0, fmt.Errorf("Invalid %v: %q", e.typ, text)
+	}
+
+	return v, nil
+}
+
+func (s SymbolStatus) String() string {
+	return symbolStatuses.format(int(s))
+}
+
+func (s SymbolStatus) MarshalText() ([]byte, error) {
+	return []byte(s.String()), nil
+}
+
+func (s *SymbolStatus) UnmarshalText(text []byte) error {
+	v, err := symbolStatuses.parse(text)
+	*s = SymbolStatus(v)
+	return err
+}
+
+func (s QuoteFieldStatus) String() string {
+	return quoteFieldStatuses.format(int(s))
+}
+
+func (s QuoteFieldStatus) MarshalText() ([]byte, error) {
+	return []byte(s.String()), nil
+}
+
+func (s *QuoteFieldStatus) UnmarshalText(text []byte) error {
+	v, err := quoteFieldStatuses.parse(text)
+	*s = QuoteFieldStatus(v)
+	return err
+}
+
+func (f QuoteField) String() string {
+	return quoteFields.format(int(f))
+}
+
+func (f QuoteField) MarshalText() ([]byte, error) {
+	return []byte(f.String()), nil
+}
+
+func (f *QuoteField) UnmarshalText(text []byte) error {
+	v, err := quoteFields.parse(text)
+	*f = QuoteField(v)
+	return err
+}
+
+func (t DataItemType) String() string {
+	return dataItemTypes.format(int(t))
+}
+
+func (t DataItemType) MarshalText() ([]byte, error) {
+	return []byte(t.String()), nil
+}
+
+func (t *DataItemType) UnmarshalText(text []byte) error {
+	v, err := dataItemTypes.parse(text)
+	*t = DataItemType(v)
+	return err
+}
+
+func (t HistoryType) String() string {
+	return historyTypes.format(int(t))
+}
+
+func (t HistoryType) MarshalText() ([]byte, error) {
+	return []byte(t.String()), nil
+}
+
+func (t *HistoryType) UnmarshalText(text []byte) error {
+	v, err := historyTypes.parse(text)
+	*t = HistoryType(v)
+	return err
+}
+
+func (c TradeCondition) String() string {
+	return tradeConditions.format(int(c))
+}
+
+func (c TradeCondition) MarshalText() ([]byte, error) {
+	return []byte(c.String()), nil
+}
+
+func (c *TradeCondition) UnmarshalText(text []byte) error {
+	v, err := tradeConditions.parse(text)
+	*c = TradeCondition(v)
+	return err
+}
+
+// String renders the set flags joined by "|", e.g.
+// "RegularMarketLastPrice|RegularMarketVolume". Unknown bits are
+// rendered in hex, and no flags as "0".
+func (f TradeFlag) String() string {
+	if f == 0 {
+		return "0"
+	}
+
+	var names []string
+	for _, n := range tradeFlagNames {
+		if f&n.flag != 0 {
+			names = append(names, n.name)
+			f &^= n.flag
+		}
+	}
+
+	if f != 0 {
+		names = append(names, fmt.Sprintf("%#x", int(f)))
+	}
+
+	return strings.Join(names, "|")
+}
+
+func (f TradeFlag) MarshalText() ([]byte, error) {
+	return []byte(f.String()), nil
+}
+
+func (f *TradeFlag) UnmarshalText(text []byte) error {
+	var flags TradeFlag
+	for _, s := range strings.Split(string(text), "|") {
+		flag, err := parseTradeFlag(s)
+		if err != nil {
+			return fmt.Errorf("Invalid TradeFlag: %q", text)
+		}
+
+		flags |= flag
+	}
+
+	*f = flags
+	return nil
+}
+
+func parseTradeFlag(s string) (TradeFlag, error) {
+	for _, n := range tradeFlagNames {
+		if s == n.name {
+			return n.flag, nil
+		}
+	}
+
+	v, err := strconv.ParseInt(s, 0, 64)
+	return TradeFlag(v), err
+}
+
+func (t TickType) String() string {
+	if name, ok := tickTypeNames[t]; ok {
+		return name
+	}
+
+	return "TickType(" + strconv.Quote(string(t)) + ")"
+}
+
+func (t TickType) MarshalText() ([]byte, error) {
+	return []byte(t.String()), nil
+}
+
+// UnmarshalText accepts either the name of a tick type or
+// its code as sent by the server ("T" or "Q").
+func (t *TickType) UnmarshalText(text []byte) error {
+	s := string(text)
+	for code, name := range tickTypeNames {
+		if s == name || s == string(code) {
+			*t = code
+			return nil
+		}
+	}
+
+	if strings.HasPrefix(s, "TickType(") && strings.HasSuffix(s, ")") {
+		if code, err := strconv.Unquote(s[len("TickType(") : len(s)-1]); err == nil {
+			*t = TickType(code)
+			return nil
+		}
+	}
+
+	return fmt.Errorf("Invalid TickType: %q", text)
+}
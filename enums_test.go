@@ -0,0 +1,127 @@
+package activetick
+
+import (
+	"encoding"
+	"encoding/json"
+	"testing"
+)
+
+func TestEnumString(t *testing.T) {
+	tests := []struct {
+		value    interface{ String() string }
+		expected string
+	}{
+		{SymbolStatusNoPermission, "NoPermission"},
+		{SymbolStatus(9), "SymbolStatus(9)"},
+		{QuoteFieldBidPrice, "BidPrice"},
+		{DataDateTime, "DateTime"},
+		{HistoryTypeWeekly, "Weekly"},
+		{TradeConditionFormT, "FormT"},
+		{TradeConditionRegular, "Regular"},
+		{TradeFlag(0), "0"},
+		{TradeFlagRegularMarketLastPrice | TradeFlagRegularMarketVolume, "RegularMarketLastPrice|RegularMarketVolume"},
+		{TradeFlagHighPrice | 0x800, "HighPrice|0x800"},
+		{TickTypeTrade, "Trade"},
+		{TickType("X"), `TickType("X")`},
+	}
+
+	for _, tt := range tests {
+		if got := tt.value.String(); got != tt.expected {
+			t.Errorf("Expected %q, got %q", tt.expected, got)
+		}
+	}
+}
+
+func TestEnumTextRoundTrip(t *testing.T) {
+	type record struct {
+		Status    SymbolStatus
+		Field     QuoteField
+		DataType  DataItemType
+		History   HistoryType
+		Condition TradeConditions
+		Flags     TradeFlag
+		Type      TickType
+	}
+
+	tests := []record{
+		{
+			Status:    SymbolStatusSuccess,
+			Field:     QuoteFieldLastTradeDateTime,
+			DataType:  DataPrice,
+			History:   HistoryTypeIntraday,
+			Condition: TradeConditions{TradeConditionInterMarketSweep, TradeConditionOpened},
+			Flags:     TradeFlagRegularMarketLastPrice | TradeFlagDayLowPrice,
+			Type:      TickTypeQuote,
+		},
+		{
+			Status:    SymbolStatus(7),
+			Field:     QuoteField(99),
+			Condition: TradeConditions{TradeCondition(50)},
+			Flags:     TradeFlagOpenPrice | 0x1000,
+		},
+	}
+
+	for _, expected := range tests {
+		buf, err := json.Marshal(expected)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var got record
+		if err := json.Unmarshal(buf, &got); err != nil {
+			t.Fatalf("%s: %v", buf, err)
+		}
+
+		if got != expected {
+			t.Errorf("%s: expected %+v, got %+v", buf, expected, got)
+		}
+	}
+}
+
+func TestEnumUnmarshalText(t *testing.T) {
+	tests := []struct {
+		text     string
+		value    encoding.TextUnmarshaler
+		expected interface{}
+	}{
+		{"12", new(TradeCondition), TradeConditionFormT},
+		{"T", new(TickType), TickTypeTrade},
+		{"HighPrice|4|0x8", new(TradeFlag), TradeFlagHighPrice | TradeFlagLowPrice},
+		{"Daily", new(HistoryType), HistoryTypeDaily},
+	}
+
+	for _, tt := range tests {
+		if err := tt.value.UnmarshalText([]byte(tt.text)); err != nil {
+			t.Errorf("%q: %v", tt.text, err)
+			continue
+		}
+
+		var got interface{}
+		switch v := tt.value.(type) {
+		case *TradeCondition:
+			got = *v
+		case *TickType:
+			got = *v
+		case *TradeFlag:
+			got = *v
+		case *HistoryType:
+			got = *v
+		}
+
+		if got != tt.expected {
+			t.Errorf("%q: expected %v, got %v", tt.text, tt.expected, got)
+		}
+	}
+
+	for _, text := range []string{"Bogus", "TradeCondition(x)", ""} {
+		var c TradeCondition
+		if err := c.UnmarshalText([]byte(text)); err == nil {
+			t.Errorf("%q: expected error, got %v", text, c)
+		}
+	}
+
+	var f TradeFlag
+	if err := f.UnmarshalText([]byte("HighPrice|Bogus")); err == nil {
+		t.Errorf("Expected error, got %v", f)
+	}
+}
@@ -0,0 +1,70 @@
+package activetick
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// Errors corresponding to the unsuccessful SymbolStatus values.
+var (
+	ErrSymbolInvalid      = errors.New("Invalid symbol")
+	ErrSymbolUnavailable  = errors.New("Symbol unavailable")
+	ErrSymbolNoPermission = errors.New("No permission for symbol")
+)
+
+// ErrTickOverflow is returned by PagingClient when more ticks than fit in
+// a single page share the same second. Requests have a resolution of one
+// second, so paging cannot advance past such a second.
+var ErrTickOverflow = errors.New("Too many ticks in one second to page")
+
+// Err returns the error corresponding to the status, or nil
+// if the status is SymbolStatusSuccess.
+func (s SymbolStatus) Err() error {
+	switch s {
+	case SymbolStatusSuccess:
+		return nil
+	case SymbolStatusInvalid:
+		return ErrSymbolInvalid
+	case SymbolStatusUnavailable:
+		return ErrSymbolUnavailable
+	case SymbolStatusNoPermission:
+		return ErrSymbolNoPermission
+	default:
+		return fmt.Errorf("Unknown symbol status: %d", int(s))
+	}
+}
+
+// APIError is returned when the ActiveTick HTTP server responds
+// with a status other than 200 OK.
+type APIError struct {
+	StatusCode int
+	Status     string
+	Body       string
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("%v: %v", e.Status, e.Body)
+}
+
+// Temporary reports whether the request may succeed if it is retried,
+// i.e. the server is overloaded or failed.
+func (e *APIError) Temporary() bool {
+	return e.StatusCode >= 500 || e.StatusCode == http.StatusTooManyRequests
+}
+
+// ParseError is returned when a row of a response cannot be parsed.
+type ParseError struct {
+	// Row is the 1-based index of the row in the response.
+	Row    int
+	Record []string
+	Err    error
+}
+
+func (e *ParseError) Error() string {
+	return fmt.Sprintf("Row %d %v: %v", e.Row, e.Record, e.Err)
+}
+
+func (e *ParseError) Unwrap() error {
+	return e.Err
+}
@@ -0,0 +1,57 @@
+package activetick
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestAPIError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "server busy", http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	client := NewClient(server.Client(), server.URL)
+	_, err := client.GetBarData(&BarDataRequest{Symbol: "SPY"})
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("Expected *APIError, got %v", err)
+	}
+
+	if apiErr.StatusCode != http.StatusServiceUnavailable || !apiErr.Temporary() {
+		t.Errorf("Unexpected API error: %+v", apiErr)
+	}
+}
+
+func TestParseError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintln(w, "20101101093000,26.880000,26.900000,26.860000,26.890000,1175094")
+		fmt.Fprintln(w, "20101101093100,26.890000,26.910000,bad,26.870000,283043")
+	}))
+	defer server.Close()
+
+	client := NewClient(server.Client(), server.URL)
+	_, err := client.GetBarData(&BarDataRequest{Symbol: "SPY"})
+	var parseErr *ParseError
+	if !errors.As(err, &parseErr) {
+		t.Fatalf("Expected *ParseError, got %v", err)
+	}
+
+	if parseErr.Row != 2 {
+		t.Errorf("Expected error in row %d, got %d", 2, parseErr.Row)
+	}
+}
+
+func TestSymbolStatusErr(t *testing.T) {
+	if err := SymbolStatusSuccess.Err(); err != nil {
+		t.Errorf("Expected nil error for success, got %v", err)
+	}
+
+	err := fmt.Errorf("AAPL: %w", SymbolStatusNoPermission.Err())
+	if !errors.Is(err, ErrSymbolNoPermission) {
+		t.Errorf("Expected %v, got %v", ErrSymbolNoPermission, err)
+	}
+}
@@ -0,0 +1,119 @@
+package activetick
+
+import (
+	"strings"
+)
+
+// Countries in which venues are located, as ISO 3166 codes.
+const (
+	CountryUS = "US"
+	CountryCA = "CA"
+)
+
+// Venue describes a trading venue or feed identified by an Exchange code.
+type Venue struct {
+	Exchange Exchange
+	Name     string
+	// MIC is the ISO 10383 market identifier code of the venue. It is empty
+	// for consolidated feeds and composites, which are not venues.
+	MIC string
+	// Country is the ISO 3166 code of the venue's country,
+	// or empty if it has none.
+	Country string
+	// QuoteOnly is true if the venue is a SIP participant that contributes
+	// quotes, but not trades. It is false for consolidated feeds such as
+	// CQS and CTS, which carry the quotes or trades of all participants.
+	QuoteOnly bool
+}
+
+var usVenues = []Venue{
+	{ExchangeAMEX, "NYSE American", "XASE", CountryUS, false},
+	{ExchangeNasdaqOmxBx, "Nasdaq OMX BX", "XBOS", CountryUS, false},
+	{ExchangeNationalStockExchange, "National Stock Exchange", "XCIS", CountryUS, false},
+	{ExchangeFinraAdf, "FINRA Alternative Display Facility", "XADF", CountryUS, false},
+	{ExchangeCQS, "Consolidated Quote System", "", CountryUS, false},
+	{ExchangeInternationalSecuritiesExchange, "International Securities Exchange", "XISX", CountryUS, false},
+	{ExchangeEdgaExchange, "EDGA Exchange", "EDGA", CountryUS, false},
+	{ExchangeEdgxExchange, "EDGX Exchange", "EDGX", CountryUS, false},
+	{ExchangeChicagoStockExchange, "Chicago Stock Exchange", "XCHI", CountryUS, false},
+	{ExchangeNyseEuronext, "New York Stock Exchange", "XNYS", CountryUS, false},
+	{ExchangeNyseArcaExchange, "NYSE Arca", "ARCX", CountryUS, false},
+	{ExchangeNasdaqOmx, "Nasdaq", "XNAS", CountryUS, false},
+	{ExchangeCTS, "Consolidated Tape System", "", CountryUS, false},
+	{ExchangeCTANasdaqOMX, "Nasdaq (CTA)", "XNAS", CountryUS, false},
+	{ExchangeOTCBB, "OTC Bulletin Board", "XOTC", CountryUS, false},
+	{ExchangeNNOTC, "Non-Nasdaq OTC", "OTCM", CountryUS, false},
+	{ExchangeChicagoBoardOptionsExchange, "Chicago Board Options Exchange", "XCBO", CountryUS, false},
+	{ExchangeNasdaqOmxPhlx, "Nasdaq OMX PHLX", "XPHL", CountryUS, false},
+	{ExchangeBatsYExchange, "BATS Y-Exchange", "BATY", CountryUS, false},
+	{ExchangeBatsExchange, "BATS Exchange", "BATS", CountryUS, false},
+	{ExchangeForex, "Forex", "", "", false},
+	{ExchangeComposite, "Composite", "", "", false},
+}
+
+var caVenues = []Venue{
+	{ExchangeCanadaToronto, "Toronto Stock Exchange", "XTSE", CountryCA, false},
+	{ExchangeCanadaVenture, "TSX Venture Exchange", "XTSX", CountryCA, false},
+}
+
+// canadianSuffixes are the symbol suffixes that denote a Canadian listing.
+var canadianSuffixes = []string{":CA", ".TO", ".V"}
+
+// SymbolCountry returns the country of symbol's listing: CountryCA
+// if it has a Canadian suffix (":CA", ".TO" or ".V"), otherwise CountryUS.
+func SymbolCountry(symbol string) string {
+	symbol = strings.ToUpper(symbol)
+	for _, suffix := range canadianSuffixes {
+		if strings.HasSuffix(symbol, suffix) {
+			return CountryCA
+		}
+	}
+
+	return CountryUS
+}
+
+// LookupVenue returns the venue that code refers to in records for
+// symbol. A code used by both a US and a Canadian venue refers to the
+// venue in the country of symbol's listing (see SymbolCountry).
+func LookupVenue(code Exchange, symbol string) (Venue, bool) {
+	return LookupVenueInCountry(code, SymbolCountry(symbol))
+}
+
+// LookupVenueInCountry returns the venue identified by code, preferring
+// venues in country if the code is shared. Venues are returned for codes
+// that exist only in another country.
+func LookupVenueInCountry(code Exchange, country string) (Venue, bool) {
+	first, second := usVenues, caVenues
+	if country == CountryCA {
+		first, second = caVenues, usVenues
+	}
+
+	for _, venues := range [][]Venue{first, second} {
+		for _, venue := range venues {
+			if venue.Exchange == code {
+				return venue, true
+			}
+		}
+	}
+
+	return Venue{}, false
+}
+
+// LookupMIC returns the venue with the given market identifier code.
+// Where several codes share a MIC, the primary one is returned, e.g.
+// ExchangeNasdaqOmx rather than ExchangeCTANasdaqOMX for XNAS.
+func LookupMIC(mic string) (Venue, bool) {
+	if mic == "" {
+		return Venue{}, false
+	}
+
+	for _, venues := range [][]Venue{usVenues, caVenues} {
+		for _, venue := range venues {
+			if venue.MIC == mic {
+				return venue, true
+			}
+		}
+	}
+
+	return Venue{}, false
+}
@@ -0,0 +1,76 @@
+package activetick
+
+import (
+	"testing"
+)
+
+func TestLookupVenue(t *testing.T) {
+	tests := []struct {
+		code   Exchange
+		symbol string
+		mic    string
+	}{
+		{ExchangeCTANasdaqOMX, "IBM", "XNAS"},
+		{ExchangeCanadaToronto, "RY.TO", "XTSE"},
+		{ExchangeCanadaToronto, "RY:CA", "XTSE"},
+		{ExchangeCanadaVenture, "ABC.V", "XTSX"},
+		{ExchangeCanadaVenture, "ABC", "XTSX"},
+		{ExchangeNyseArcaExchange, "SPY", "ARCX"},
+		{ExchangeNyseArcaExchange, "RY.TO", "ARCX"},
+		{ExchangeComposite, "SPY", ""},
+	}
+
+	for _, tt := range tests {
+		venue, ok := LookupVenue(tt.code, tt.symbol)
+		if !ok {
+			t.Errorf("%q %v: venue not found", tt.code, tt.symbol)
+			continue
+		}
+
+		if venue.MIC != tt.mic {
+			t.Errorf("%q %v: expected MIC %q, got %q", tt.code, tt.symbol, tt.mic, venue.MIC)
+		}
+	}
+
+	if venue, ok := LookupVenue("?", "SPY"); ok {
+		t.Errorf("Expected no venue, got %+v", venue)
+	}
+
+	for _, code := range []Exchange{ExchangeCQS, ExchangeCTS} {
+		if venue, _ := LookupVenue(code, "SPY"); venue.QuoteOnly || venue.MIC != "" {
+			t.Errorf("%q: expected a consolidated feed, got %+v", code, venue)
+		}
+	}
+
+	// Modifying a returned venue does not affect later lookups.
+	venue, _ := LookupVenue(ExchangeNasdaqOmx, "SPY")
+	venue.MIC = "XXXX"
+	if venue, _ := LookupVenue(ExchangeNasdaqOmx, "SPY"); venue.MIC != "XNAS" {
+		t.Errorf("Expected MIC %q, got %q", "XNAS", venue.MIC)
+	}
+}
+
+func TestLookupMIC(t *testing.T) {
+	tests := []struct {
+		mic      string
+		exchange Exchange
+		country  string
+	}{
+		{"XNAS", ExchangeNasdaqOmx, CountryUS},
+		{"XTSE", ExchangeCanadaToronto, CountryCA},
+		{"BATS", ExchangeBatsExchange, CountryUS},
+	}
+
+	for _, tt := range tests {
+		venue, ok := LookupMIC(tt.mic)
+		if !ok || venue.Exchange != tt.exchange || venue.Country != tt.country {
+			t.Errorf("%v: expected %q in %v, got %+v", tt.mic, tt.exchange, tt.country, venue)
+		}
+	}
+
+	for _, mic := range []string{"", "XLON"} {
+		if venue, ok := LookupMIC(mic); ok {
+			t.Errorf("%q: expected no venue, got %+v", mic, venue)
+		}
+	}
+}
@@ -2,6 +2,8 @@ package activetick
 
 import (
 	"time"
+
+	"github.com/timpalpant/go-activetick/calendar"
 )
 
 type SymbolStatus int
@@ -13,6 +15,15 @@ const (
 	SymbolStatusNoPermission SymbolStatus = 4
 )
 
+type QuoteFieldStatus int
+
+const (
+	QuoteFieldStatusSuccess     QuoteFieldStatus = 1
+	QuoteFieldStatusInvalid     QuoteFieldStatus = 2
+	QuoteFieldStatusUnavailable QuoteFieldStatus = 3
+	QuoteFieldStatusDenied      QuoteFieldStatus = 4
+)
+
 type QuoteField int
 
 const (
@@ -79,6 +90,7 @@ type QuoteDataResponse struct {
 
 type QuoteSnapshotRecord struct {
 	Symbol                           string
+	Status                           SymbolStatus
 	OpenPrice                        float64
 	PreviousClosePrice               float64
 	ClosePrice                       float64
@@ -121,7 +133,7 @@ type QuoteStreamRequest struct {
 type TradeStreamRecord struct {
 	Symbol          string
 	Flags           TradeFlag
-	TradeConditions [4]TradeCondition
+	TradeConditions TradeConditions
 	LastExchange    Exchange
 	LastPrice       float64
 	LastSize        int
@@ -167,6 +179,9 @@ type BarDataRecord struct {
 	Low    float64
 	Close  float64
 	Volume int64
+	// Session is the trading session at Time of an intraday bar.
+	// It is SessionClosed for daily and weekly bars.
+	Session calendar.Session
 }
 
 type TickDataRequest struct {
@@ -194,13 +209,14 @@ type TickRecord struct {
 	LastPrice    float64
 	LastSize     int64
 	LastExchange Exchange
-	Condition    [4]TradeCondition
+	Condition    TradeConditions
 	BidPrice     float64
 	AskPrice     float64
 	BidSize      int64
 	AskSize      int64
 	BidExchange  Exchange
 	AskExchange  Exchange
+	Session      calendar.Session
 }
 
 type OptionChainRequest struct {
@@ -208,7 +224,27 @@ type OptionChainRequest struct {
 }
 
 type OptionChainResponse struct {
-	Records []string
+	Records []*OptionContract
+}
+
+type OptionType string
+
+const (
+	OptionTypeCall OptionType = "C"
+	OptionTypePut  OptionType = "P"
+)
+
+// OptionContract is a single listed option. Symbol is the option symbol
+// as returned by the server, and may be used as the Symbol of a
+// TickDataRequest or BarDataRequest.
+type OptionContract struct {
+	Symbol     string
+	Underlying string
+	// Expiration is midnight at the start of the expiration date,
+	// in America/New_York.
+	Expiration time.Time
+	Type       OptionType
+	Strike     float64
 }
 
 type TradeFlag int
@@ -269,6 +305,9 @@ const (
 	TradeConditionYellowFlag                    TradeCondition = 36
 )
 
+// Exchange is the code used by ActiveTick to identify a venue. Codes are
+// not unique: ExchangeCTANasdaqOMX and ExchangeCanadaToronto are both "T".
+// Use LookupVenue to resolve a code to a Venue.
 type Exchange string
 
 const (
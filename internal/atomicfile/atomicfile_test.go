@@ -0,0 +1,41 @@
+package atomicfile
+
+import (
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestWrite(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "a", "b.txt")
+	write := func(content string, err error) error {
+		return Write(path, func(w io.Writer) error {
+			if _, err := io.WriteString(w, content); err != nil {
+				return err
+			}
+
+			return err
+		})
+	}
+
+	if err := write("first", nil); err != nil {
+		t.Fatal(err)
+	}
+
+	// A failed write leaves the previous content in place.
+	if err := write("second", errors.New("failed")); err == nil {
+		t.Fatal("Expected error")
+	}
+
+	buf, err := os.ReadFile(path)
+	if err != nil || string(buf) != "first" {
+		t.Errorf("Expected %q, got %q: %v", "first", buf, err)
+	}
+
+	entries, _ := os.ReadDir(filepath.Dir(path))
+	if len(entries) != 1 {
+		t.Errorf("Expected temporary files to be removed, got %v", entries)
+	}
+}
@@ -0,0 +1,119 @@
+package activetick
+
+import (
+	"context"
+	"encoding/csv"
+	"io"
+	"time"
+)
+
+// TickIterator iterates over tick records as they are read from the
+// server, without loading the entire response into memory.
+//
+//	it, err := client.TickDataIterator(ctx, req)
+//	if err != nil {
+//		return err
+//	}
+//	defer it.Close()
+//
+//	for it.Next() {
+//		record := it.Record()
+//		...
+//	}
+//
+//	return it.Err()
+type TickIterator interface {
+	// Next advances the iterator to the next record, which will then be
+	// available through Record. It returns false when there are no more
+	// records or an error occurred.
+	Next() bool
+	// Record returns the most recent record read by Next.
+	Record() *TickRecord
+	// Err returns the first error encountered by the iterator, if any.
+	Err() error
+	// Close releases the underlying HTTP response.
+	Close() error
+}
+
+// TickDataIterator issues a /tickData request and returns an iterator
+// that parses records incrementally from the response body.
+// The caller must close the returned iterator.
+func (c *Client) TickDataIterator(ctx context.Context, req *TickDataRequest) (TickIterator, error) {
+	resp, err := c.get(ctx, "/tickData", tickDataValues(req, c.location))
+	if err != nil {
+		return nil, err
+	}
+
+	return c.newTickIterator(resp.Body), nil
+}
+
+func (c *Client) newTickIterator(body io.ReadCloser) *tickIterator {
+	return &tickIterator{
+		body:     body,
+		reader:   csv.NewReader(body),
+		location: c.location,
+	}
+}
+
+type tickIterator struct {
+	body     io.ReadCloser
+	reader   *csv.Reader
+	location *time.Location
+	row      int
+	record   *TickRecord
+	err      error
+}
+
+func (it *tickIterator) Next() bool {
+	it.record = nil
+	if it.err != nil {
+		return false
+	}
+
+	row, err := it.reader.Read()
+	if err != nil {
+		if err != io.EOF {
+			it.err = err
+		}
+
+		return false
+	}
+
+	it.row++
+	record, err := parseTickData(row, it.location)
+	if err != nil {
+		it.err = &ParseError{Row: it.row, Record: row, Err: err}
+		return false
+	}
+
+	it.record = record
+	return true
+}
+
+func (it *tickIterator) Record() *TickRecord {
+	return it.record
+}
+
+func (it *tickIterator) Err() error {
+	return it.err
+}
+
+func (it *tickIterator) Close() error {
+	return it.body.Close()
+}
+
+// collectTicks reads all remaining records from it into a response.
+func collectTicks(it TickIterator) (*TickDataResponse, error) {
+	resp := &TickDataResponse{
+		Records: []*TickRecord{},
+	}
+	for it.Next() {
+		resp.Records = append(resp.Records, it.Record())
+	}
+
+	if err := it.Err(); err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
@@ -0,0 +1,45 @@
+package activetick
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+)
+
+func TestTickDataIterator(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.ServeFile(w, r, filepath.Join("testdata", "tickDataResponse.csv"))
+	}))
+	defer server.Close()
+
+	rows, err := loadCSVData("tickDataResponse.csv")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	client := NewPagingClient(NewClient(server.Client(), server.URL))
+	it, err := client.TickIterator(context.Background(), &TickDataRequest{Symbol: "GOOG"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer it.Close()
+
+	n := 0
+	for it.Next() {
+		if it.Record() == nil {
+			t.Fatal("Expected non-nil record")
+		}
+
+		n++
+	}
+
+	if err := it.Err(); err != nil {
+		t.Fatal(err)
+	}
+
+	if n != len(rows) {
+		t.Errorf("Expected %d records, got %d", len(rows), n)
+	}
+}
@@ -0,0 +1,115 @@
+package activetick
+
+import (
+	"context"
+	"io"
+	"sync"
+	"time"
+)
+
+// WithRateLimit limits the Client to starting at most rps requests
+// per second, shared by all goroutines using the Client. Each retry
+// of a request counts as a separate request. If rps is not positive,
+// requests are not rate limited.
+func WithRateLimit(rps float64) ClientOption {
+	return func(c *Client) {
+		if rps <= 0 {
+			c.rateLimiter = nil
+			return
+		}
+
+		c.rateLimiter = &rateLimiter{
+			interval: time.Duration(float64(time.Second) / rps),
+		}
+	}
+}
+
+// WithMaxInFlight limits the number of requests the Client will have
+// outstanding at once, shared by all goroutines using the Client.
+// A request remains in flight until its response has been read, so
+// iterators and quote streams hold their slot until they are closed.
+// If n is not positive, the number of requests in flight is not limited.
+func WithMaxInFlight(n int) ClientOption {
+	return func(c *Client) {
+		if n <= 0 {
+			c.inFlight = nil
+			return
+		}
+
+		c.inFlight = make(chan struct{}, n)
+	}
+}
+
+// rateLimiter spaces requests evenly at a fixed interval.
+type rateLimiter struct {
+	interval time.Duration
+
+	mu   sync.Mutex
+	next time.Time
+}
+
+// wait blocks until the next request may start or ctx is done. A slot is
+// only reserved once it is reached, so requests that are cancelled while
+// waiting do not delay others.
+func (l *rateLimiter) wait(ctx context.Context) error {
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		l.mu.Lock()
+		now := time.Now()
+		if !now.Before(l.next) {
+			l.next = now.Add(l.interval)
+			l.mu.Unlock()
+			return nil
+		}
+		d := l.next.Sub(now)
+		l.mu.Unlock()
+
+		timer := time.NewTimer(d)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// acquire waits for the rate limit and a free in-flight slot. The returned
+// function must be called to release the slot once the request is done.
+func (c *Client) acquire(ctx context.Context) (func(), error) {
+	if c.rateLimiter != nil {
+		if err := c.rateLimiter.wait(ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	if c.inFlight == nil {
+		return func() {}, nil
+	}
+
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case c.inFlight <- struct{}{}:
+	}
+
+	var once sync.Once
+	return func() {
+		once.Do(func() { <-c.inFlight })
+	}, nil
+}
+
+// releasingBody releases its in-flight slot when the body is closed.
+type releasingBody struct {
+	io.ReadCloser
+	release func()
+}
+
+func (b *releasingBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.release()
+	return err
+}
@@ -0,0 +1,114 @@
+package activetick
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestMaxInFlight(t *testing.T) {
+	var current, peak int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := atomic.AddInt32(&current, 1)
+		defer atomic.AddInt32(&current, -1)
+		for {
+			p := atomic.LoadInt32(&peak)
+			if n <= p || atomic.CompareAndSwapInt32(&peak, p, n) {
+				break
+			}
+		}
+
+		time.Sleep(10 * time.Millisecond)
+		fmt.Fprintln(w, "20101101093000,26.880000,26.900000,26.860000,26.890000,1175094")
+	}))
+	defer server.Close()
+
+	client := NewClient(server.Client(), server.URL, WithMaxInFlight(2))
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := client.GetBarData(&BarDataRequest{Symbol: "SPY"}); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if peak > 2 {
+		t.Errorf("Expected at most %d requests in flight, got %d", 2, peak)
+	}
+}
+
+func TestRateLimit(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintln(w, "20101101093000,26.880000,26.900000,26.860000,26.890000,1175094")
+	}))
+	defer server.Close()
+
+	client := NewClient(server.Client(), server.URL, WithRateLimit(100))
+	start := time.Now()
+	for i := 0; i < 5; i++ {
+		if _, err := client.GetBarData(&BarDataRequest{Symbol: "SPY"}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if elapsed := time.Since(start); elapsed < 40*time.Millisecond {
+		t.Errorf("Expected 5 requests at 100/s to take at least 40ms, took %v", elapsed)
+	}
+}
+
+func TestNonPositiveLimits(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintln(w, "20101101093000,26.880000,26.900000,26.860000,26.890000,1175094")
+	}))
+	defer server.Close()
+
+	for _, opt := range []ClientOption{
+		WithRateLimit(0),
+		WithRateLimit(-1),
+		WithMaxInFlight(0),
+		WithMaxInFlight(-1),
+	} {
+		client := NewClient(server.Client(), server.URL, opt)
+		if client.rateLimiter != nil || client.inFlight != nil {
+			t.Errorf("Expected non-positive limit to be ignored")
+		}
+
+		if _, err := client.GetBarData(&BarDataRequest{Symbol: "SPY"}); err != nil {
+			t.Error(err)
+		}
+	}
+}
+
+func TestRateLimitCancelled(t *testing.T) {
+	l := &rateLimiter{interval: 50 * time.Millisecond}
+	if err := l.wait(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	// Requests cancelled while waiting do not use up the rate budget.
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	for i := 0; i < 10; i++ {
+		if err := l.wait(ctx); err == nil {
+			t.Fatal("Expected error from cancelled wait")
+		}
+	}
+
+	start := time.Now()
+	if err := l.wait(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	if elapsed := time.Since(start); elapsed > 200*time.Millisecond {
+		t.Errorf("Expected to wait at most one interval, waited %v", elapsed)
+	}
+}
@@ -1,6 +1,8 @@
 package activetick
 
 import (
+	"context"
+	"fmt"
 	"time"
 )
 
@@ -23,17 +25,35 @@ func NewPagingClient(client *Client) *PagingClient {
 // If there are more than that, only the latest 20,000 in time are returned.
 // So to fetch all data we need to page backward.
 func (pc *PagingClient) GetBarData(req *BarDataRequest) (*BarDataResponse, error) {
-	resp := &BarDataResponse{}
+	return pc.GetBarDataContext(context.Background(), req)
+}
+
+// GetBarDataContext is like GetBarData, but stops paging and cancels
+// any outstanding request when ctx is done.
+func (pc *PagingClient) GetBarDataContext(ctx context.Context, req *BarDataRequest) (*BarDataResponse, error) {
+	// Pages are fetched newest first.
+	var pages [][]*BarDataRecord
+	n := 0
 
 	for {
-		page, err := pc.client.GetBarData(req)
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		page, err := pc.client.GetBarDataContext(ctx, req)
 		if err != nil {
 			return nil, err
 		}
 
-		resp.Records = append(page.Records, resp.Records...)
-		oldestTime := resp.Records[0].Time
-		if len(page.Records) < maxBars || !oldestTime.Before(req.EndTime) {
+		if len(page.Records) == 0 {
+			break
+		}
+
+		pages = append(pages, page.Records)
+		n += len(page.Records)
+		oldestTime := page.Records[0].Time
+		if len(page.Records) < maxBars ||
+			!oldestTime.Before(req.EndTime) || !oldestTime.After(req.BeginTime) {
 			break
 		}
 
@@ -46,46 +66,222 @@ func (pc *PagingClient) GetBarData(req *BarDataRequest) (*BarDataResponse, error
 		}
 	}
 
+	resp := &BarDataResponse{
+		Records: make([]*BarDataRecord, 0, n),
+	}
+
+	for i := len(pages) - 1; i >= 0; i-- {
+		resp.Records = append(resp.Records, pages[i]...)
+	}
+
 	return resp, nil
 }
 
 // The HTTP API will return at most 100,000 ticks.
 // If there are more than that, only the first 100,000 in time are returned.
 // So to fetch all data we need to page forward.
+//
+// Since requests have a resolution of one second, the ticks of the last
+// second of a full page may be incomplete. They are discarded and the
+// next page starts from that second, so every tick is returned exactly
+// once and in order. If a single second contains a full page of ticks,
+// no progress can be made and ErrTickOverflow is returned.
 func (pc *PagingClient) GetTickData(req *TickDataRequest) (*TickDataResponse, error) {
-	resp := &TickDataResponse{}
+	return pc.GetTickDataContext(context.Background(), req)
+}
 
-	for {
-		page, err := pc.client.GetTickData(req)
-		if err != nil {
-			return nil, err
-		}
+// GetTickDataContext is like GetTickData, but stops paging and cancels
+// any outstanding request when ctx is done.
+func (pc *PagingClient) GetTickDataContext(ctx context.Context, req *TickDataRequest) (*TickDataResponse, error) {
+	it, err := pc.TickIterator(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	defer it.Close()
 
-		resp.Records = append(resp.Records, page.Records...)
+	return collectTicks(it)
+}
 
-		latestTime := page.Records[len(page.Records)-1].Time
-		latestTime = latestTime.Truncate(time.Second)
-		if len(page.Records) < maxTicks || !latestTime.After(req.BeginTime) {
-			break
+// TickIterator returns an iterator over all ticks in the requested range,
+// transparently paging forward as each page is exhausted. Only the ticks
+// of the most recent second are held in memory at a time.
+// The caller must close the returned iterator.
+func (pc *PagingClient) TickIterator(ctx context.Context, req *TickDataRequest) (TickIterator, error) {
+	page, err := pc.client.TickDataIterator(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	return &pagingTickIterator{
+		ctx:    ctx,
+		client: pc.client,
+		req:    req,
+		page:   page,
+	}, nil
+}
+
+type pagingTickIterator struct {
+	ctx    context.Context
+	client *Client
+	req    *TickDataRequest
+	page   TickIterator
+	// Number of records read from the current page.
+	count int
+	// Number of failed attempts to read the current page.
+	failures int
+	// Records in the latest second seen are held in pending until either
+	// a record from a later second arrives or the page is known to be
+	// complete, since a full page may have truncated that second.
+	second  time.Time
+	pending []*TickRecord
+	ready   []*TickRecord
+	record  *TickRecord
+	err     error
+}
+
+func (it *pagingTickIterator) Next() bool {
+	it.record = nil
+	for len(it.ready) == 0 {
+		if it.err != nil || it.page == nil {
+			return false
+		}
+
+		if it.page.Next() {
+			it.add(it.page.Record())
+			continue
 		}
 
-		for i := len(resp.Records) - 1; i >= 0; i-- {
-			t := resp.Records[i].Time
-			if !t.Before(latestTime) {
-				resp.Records = resp.Records[:i]
-			} else {
-				break
+		if err := it.page.Err(); err != nil {
+			if err := it.retryPage(err); err != nil {
+				it.err = err
+				return false
 			}
+
+			continue
 		}
 
-		req = &TickDataRequest{
-			Symbol:    req.Symbol,
-			Trades:    req.Trades,
-			Quotes:    req.Quotes,
-			BeginTime: latestTime,
-			EndTime:   req.EndTime,
+		it.page.Close()
+		it.page = nil
+		if err := it.nextPage(); err != nil {
+			it.err = err
+			return false
 		}
 	}
 
-	return resp, nil
+	it.record = it.ready[0]
+	it.ready[0] = nil
+	it.ready = it.ready[1:]
+	return true
+}
+
+func (it *pagingTickIterator) add(record *TickRecord) {
+	it.count++
+	second := record.Time.Truncate(time.Second)
+	if second.Before(it.req.BeginTime.Truncate(time.Second)) {
+		// Never return a tick that precedes the page,
+		// since it would already have been returned.
+		return
+	}
+
+	if second.After(it.second) {
+		it.ready = append(it.ready, it.pending...)
+		it.pending = it.pending[:0]
+		it.second = second
+	}
+
+	it.pending = append(it.pending, record)
+}
+
+// nextPage is called when the current page is exhausted. If it was full,
+// the ticks of its last second are discarded and the next page is
+// requested starting from that second.
+func (it *pagingTickIterator) nextPage() error {
+	if it.count < maxTicks {
+		it.ready = append(it.ready, it.pending...)
+		it.pending = nil
+		return nil
+	}
+
+	if !it.second.After(it.req.BeginTime.Truncate(time.Second)) {
+		return fmt.Errorf("%v at %v: %w", it.req.Symbol, it.second, ErrTickOverflow)
+	}
+
+	if err := it.ctx.Err(); err != nil {
+		return err
+	}
+
+	it.pending = it.pending[:0]
+	it.req = &TickDataRequest{
+		Symbol:    it.req.Symbol,
+		Trades:    it.req.Trades,
+		Quotes:    it.req.Quotes,
+		BeginTime: it.second,
+		EndTime:   it.req.EndTime,
+	}
+
+	page, err := it.client.TickDataIterator(it.ctx, it.req)
+	if err != nil {
+		return err
+	}
+
+	it.page = page
+	it.count = 0
+	it.failures = 0
+	return nil
+}
+
+// retryPage is called when reading the current page fails with err.
+// If the client's retry policy allows, the page is requested again
+// starting from the latest second seen, whose ticks may be incomplete.
+// Ticks of earlier seconds have already been released, and are skipped
+// by add if they are repeated.
+func (it *pagingTickIterator) retryPage(err error) error {
+	it.failures++
+	policy := it.client.retry
+	if policy == nil || it.failures >= policy.MaxAttempts ||
+		it.ctx.Err() != nil || !policy.retryable(err) {
+		return err
+	}
+
+	it.page.Close()
+	it.page = nil
+	if err := policy.wait(it.ctx, it.failures); err != nil {
+		return err
+	}
+
+	if it.second.After(it.req.BeginTime) {
+		it.req = &TickDataRequest{
+			Symbol:    it.req.Symbol,
+			Trades:    it.req.Trades,
+			Quotes:    it.req.Quotes,
+			BeginTime: it.second,
+			EndTime:   it.req.EndTime,
+		}
+	}
+
+	page, err := it.client.TickDataIterator(it.ctx, it.req)
+	if err != nil {
+		return err
+	}
+
+	it.pending = it.pending[:0]
+	it.page = page
+	it.count = 0
+	return nil
+}
+
+func (it *pagingTickIterator) Record() *TickRecord {
+	return it.record
+}
+
+func (it *pagingTickIterator) Err() error {
+	return it.err
+}
+
+func (it *pagingTickIterator) Close() error {
+	if it.page != nil {
+		return it.page.Close()
+	}
+
+	return nil
 }
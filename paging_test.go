@@ -0,0 +1,328 @@
+package activetick_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"testing"
+	"testing/iotest"
+	"time"
+
+	"github.com/timpalpant/go-activetick"
+	"github.com/timpalpant/go-activetick/attest"
+)
+
+// pagingStart is 09:30 New York time, in a range without DST transitions,
+// during which the server's round-the-clock times would be ambiguous.
+var pagingStart = time.Date(2016, 6, 1, 13, 30, 0, 0, time.UTC)
+
+func makeBars(start time.Time, n int) []*activetick.BarDataRecord {
+	bars := make([]*activetick.BarDataRecord, n)
+	for i := range bars {
+		bars[i] = &activetick.BarDataRecord{
+			Time:   start.Add(time.Duration(i) * time.Minute),
+			Open:   100,
+			High:   101,
+			Low:    99,
+			Close:  100,
+			Volume: int64(i),
+		}
+	}
+
+	return bars
+}
+
+// makeTicks returns n trades, perSecond of which fall within each second.
+func makeTicks(start time.Time, n, perSecond int) []*activetick.TickRecord {
+	ticks := make([]*activetick.TickRecord, n)
+	step := time.Second / time.Duration(perSecond)
+	for i := range ticks {
+		ticks[i] = &activetick.TickRecord{
+			Type:         activetick.TickTypeTrade,
+			Time:         start.Add(time.Duration(i) * step).Truncate(time.Millisecond),
+			LastPrice:    100,
+			LastSize:     int64(i),
+			LastExchange: activetick.ExchangeNasdaqOmx,
+		}
+	}
+
+	return ticks
+}
+
+// makeBurstTicks returns trades with counts[i] ticks in the i'th second.
+func makeBurstTicks(start time.Time, counts []int) []*activetick.TickRecord {
+	var ticks []*activetick.TickRecord
+	for i, n := range counts {
+		second := start.Add(time.Duration(i) * time.Second)
+		for j := 0; j < n; j++ {
+			ticks = append(ticks, &activetick.TickRecord{
+				Type:         activetick.TickTypeTrade,
+				Time:         second.Add(time.Duration(j%1000) * time.Millisecond),
+				LastPrice:    100,
+				LastSize:     int64(len(ticks)),
+				LastExchange: activetick.ExchangeNasdaqOmx,
+			})
+		}
+	}
+
+	return ticks
+}
+
+// newPagingServer returns a server with dataset for SPY, and a client for it.
+func newPagingServer(dataset *attest.Dataset, opts ...activetick.ClientOption) (*attest.Server, *activetick.PagingClient) {
+	server := attest.NewServer()
+	server.SetDataset("SPY", dataset)
+	client := activetick.NewClient(server.Client(), server.URL, opts...)
+	return server, activetick.NewPagingClient(client)
+}
+
+func TestPagingBarData(t *testing.T) {
+	tests := []struct {
+		name  string
+		n     int
+		begin time.Time
+		end   time.Time
+	}{
+		{"holiday", 0, pagingStart, pagingStart.Add(24 * time.Hour)},
+		{"single page", 100, pagingStart, pagingStart.Add(365 * 24 * time.Hour)},
+		{"exactly MaxBars", attest.MaxBars, pagingStart, pagingStart.Add(365 * 24 * time.Hour)},
+		{"exact page boundary", 2 * attest.MaxBars, pagingStart, pagingStart.Add(365 * 24 * time.Hour)},
+		{"begins at oldest bar", attest.MaxBars, pagingStart, pagingStart.Add((attest.MaxBars - 1) * time.Minute)},
+		{"multiple pages", 2*attest.MaxBars + 123, pagingStart, pagingStart.Add(365 * 24 * time.Hour)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server, client := newPagingServer(&attest.Dataset{Bars: makeBars(pagingStart, tt.n)})
+			defer server.Close()
+
+			resp, err := client.GetBarData(&activetick.BarDataRequest{
+				Symbol:    "SPY",
+				BeginTime: tt.begin,
+				EndTime:   tt.end,
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if resp.Records == nil || len(resp.Records) != tt.n {
+				t.Fatalf("Expected %d records, got %d", tt.n, len(resp.Records))
+			}
+
+			for i, record := range resp.Records {
+				if record.Volume != int64(i) {
+					t.Fatalf("Record %d out of order: %+v", i, record)
+				}
+			}
+		})
+	}
+}
+
+func TestPagingTickData(t *testing.T) {
+	tests := []struct {
+		name      string
+		n         int
+		perSecond int
+	}{
+		{"holiday", 0, 10},
+		{"single page", 100, 10},
+		{"exactly MaxTicks", attest.MaxTicks, 10},
+		{"exact page boundary", 2 * attest.MaxTicks, 10},
+		{"multiple pages", 2*attest.MaxTicks + 7, 7},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server, client := newPagingServer(&attest.Dataset{Ticks: makeTicks(pagingStart, tt.n, tt.perSecond)})
+			defer server.Close()
+
+			resp, err := client.GetTickData(&activetick.TickDataRequest{
+				Symbol:    "SPY",
+				Trades:    true,
+				BeginTime: pagingStart,
+				EndTime:   pagingStart.Add(365 * 24 * time.Hour),
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if resp.Records == nil || len(resp.Records) != tt.n {
+				t.Fatalf("Expected %d records, got %d", tt.n, len(resp.Records))
+			}
+
+			for i, record := range resp.Records {
+				if record.LastSize != int64(i) {
+					t.Fatalf("Record %d out of order: %+v", i, record)
+				}
+			}
+		})
+	}
+}
+
+func TestPagingTickDataBursts(t *testing.T) {
+	max := attest.MaxTicks
+	ticks := makeBurstTicks(pagingStart, []int{10, 30000, 70000, 5, max - 1, 60000, 0, 1, max - 1, 3})
+	server, client := newPagingServer(&attest.Dataset{Ticks: ticks})
+	defer server.Close()
+
+	resp, err := client.GetTickData(&activetick.TickDataRequest{
+		Symbol:    "SPY",
+		Trades:    true,
+		BeginTime: pagingStart,
+		EndTime:   pagingStart.Add(time.Hour),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(resp.Records) != len(ticks) {
+		t.Fatalf("Expected %d records, got %d", len(ticks), len(resp.Records))
+	}
+
+	for i, record := range resp.Records {
+		if record.LastSize != ticks[i].LastSize {
+			t.Fatalf("Record %d differs: %+v != %+v", i, record, ticks[i])
+		}
+	}
+}
+
+func TestPagingTickDataOverflow(t *testing.T) {
+	ticks := makeBurstTicks(pagingStart, []int{5, attest.MaxTicks + 1, 5})
+	server, client := newPagingServer(&attest.Dataset{Ticks: ticks})
+	defer server.Close()
+
+	_, err := client.GetTickData(&activetick.TickDataRequest{
+		Symbol:    "SPY",
+		Trades:    true,
+		BeginTime: pagingStart,
+		EndTime:   pagingStart.Add(time.Hour),
+	})
+	if !errors.Is(err, activetick.ErrTickOverflow) {
+		t.Fatalf("Expected %v, got %v", activetick.ErrTickOverflow, err)
+	}
+}
+
+// droppingTransport drops the connection partway through the body
+// of every other response.
+type droppingTransport struct {
+	transport http.RoundTripper
+	requests  int
+}
+
+func (t *droppingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := t.transport.RoundTrip(req)
+	t.requests++
+	if err != nil || t.requests%2 == 0 {
+		return resp, err
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	resp.Body = io.NopCloser(io.MultiReader(
+		bytes.NewReader(body[:len(body)/2]),
+		iotest.ErrReader(io.ErrUnexpectedEOF)))
+	return resp, nil
+}
+
+func TestPagingTickDataResumesDroppedPage(t *testing.T) {
+	ticks := makeBurstTicks(pagingStart, []int{10, 30000, 70000, 5, 60000, 50000, 3})
+	server := attest.NewServer()
+	defer server.Close()
+	server.SetDataset("SPY", &attest.Dataset{Ticks: ticks})
+
+	transport := &droppingTransport{transport: server.Client().Transport}
+	client := activetick.NewPagingClient(activetick.NewClient(&http.Client{Transport: transport}, server.URL,
+		activetick.WithRetryPolicy(&activetick.RetryPolicy{
+			MaxAttempts:    3,
+			InitialBackoff: time.Millisecond,
+			Multiplier:     2,
+		})))
+	resp, err := client.GetTickData(&activetick.TickDataRequest{
+		Symbol:    "SPY",
+		Trades:    true,
+		BeginTime: pagingStart,
+		EndTime:   pagingStart.Add(time.Hour),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// At least two pages were each dropped once and resumed.
+	if transport.requests < 4 {
+		t.Errorf("Expected at least %d requests, got %d", 4, transport.requests)
+	}
+
+	if len(resp.Records) != len(ticks) {
+		t.Fatalf("Expected %d records, got %d", len(ticks), len(resp.Records))
+	}
+
+	for i, record := range resp.Records {
+		if record.LastSize != ticks[i].LastSize {
+			t.Fatalf("Record %d differs: %+v != %+v", i, record, ticks[i])
+		}
+	}
+}
+
+func TestGetBarDataChunked(t *testing.T) {
+	n := 3*attest.MaxBars + 17
+	server, client := newPagingServer(&attest.Dataset{Bars: makeBars(pagingStart, n)})
+	defer server.Close()
+
+	req := &activetick.BarDataRequest{
+		Symbol:    "SPY",
+		BeginTime: pagingStart,
+		EndTime:   pagingStart.Add(time.Duration(n-1) * time.Minute),
+	}
+
+	opts := activetick.ChunkOptions{Window: 7 * time.Hour, Workers: 3}
+	resp, err := client.GetBarDataChunked(context.Background(), req, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(resp.Records) != n {
+		t.Fatalf("Expected %d records, got %d", n, len(resp.Records))
+	}
+
+	for i, record := range resp.Records {
+		if record.Volume != int64(i) {
+			t.Fatalf("Record %d out of order: %+v", i, record)
+		}
+	}
+}
+
+func TestGetTickDataChunked(t *testing.T) {
+	n := 2*attest.MaxTicks + 17
+	ticks := makeTicks(pagingStart, n, 9)
+	server, client := newPagingServer(&attest.Dataset{Ticks: ticks})
+	defer server.Close()
+
+	req := &activetick.TickDataRequest{
+		Symbol:    "SPY",
+		Trades:    true,
+		BeginTime: pagingStart,
+		EndTime:   ticks[n-1].Time.Truncate(time.Second),
+	}
+
+	opts := activetick.ChunkOptions{Window: time.Hour, Workers: 4}
+	resp, err := client.GetTickDataChunked(context.Background(), req, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(resp.Records) != n {
+		t.Fatalf("Expected %d records, got %d", n, len(resp.Records))
+	}
+
+	for i, record := range resp.Records {
+		if record.LastSize != int64(i) {
+			t.Fatalf("Record %d out of order: %+v", i, record)
+		}
+	}
+}
@@ -0,0 +1,49 @@
+package activetick
+
+import (
+	"encoding/csv"
+	"io"
+	"io/ioutil"
+
+	"github.com/timpalpant/go-activetick/calendar"
+)
+
+// ReadBarData parses bars in the CSV format of a /barData response,
+// for example from a saved response. Times are read as America/New_York,
+// and bars are tagged with their session as intraday bars.
+func ReadBarData(r io.Reader) ([]*BarDataRecord, error) {
+	rows, err := csv.NewReader(r).ReadAll()
+	if err != nil {
+		return nil, err
+	}
+
+	records := make([]*BarDataRecord, 0, len(rows))
+	for i, row := range rows {
+		record, err := parseBarData(row, calendar.Location)
+		if err != nil {
+			return nil, &ParseError{Row: i + 1, Record: row, Err: err}
+		}
+		record.Session = calendar.SessionAt(record.Time)
+
+		records = append(records, record)
+	}
+
+	return records, nil
+}
+
+// ReadTickData parses ticks in the CSV format of a /tickData response,
+// for example from a saved response. Times are read as America/New_York.
+func ReadTickData(r io.Reader) ([]*TickRecord, error) {
+	it := &tickIterator{
+		body:     ioutil.NopCloser(r),
+		reader:   csv.NewReader(r),
+		location: calendar.Location,
+	}
+
+	resp, err := collectTicks(it)
+	if err != nil {
+		return nil, err
+	}
+
+	return resp.Records, nil
+}
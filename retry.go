@@ -0,0 +1,148 @@
+package activetick
+
+import (
+	"context"
+	"errors"
+	"io"
+	"math/rand"
+	"net"
+	"syscall"
+	"time"
+)
+
+// RetryPolicy controls how a Client retries failed requests.
+// Each request is retried independently, so a PagingClient
+// retries each page rather than the entire fetch.
+//
+// Responses that fail partway through are retried from the beginning,
+// except for streamed responses: errors while reading from
+// TickDataIterator or StreamQuotes are returned to the caller.
+// PagingClient's tick iterator instead resumes a page that fails partway
+// from the start of the latest second it has read.
+type RetryPolicy struct {
+	// MaxAttempts is the total number of attempts made for a request,
+	// including the first. Values less than 2 disable retries.
+	MaxAttempts int
+	// InitialBackoff is the delay before the first retry.
+	InitialBackoff time.Duration
+	// MaxBackoff caps the delay between attempts.
+	MaxBackoff time.Duration
+	// Multiplier is the factor by which the delay grows after each retry.
+	Multiplier float64
+	// Jitter randomizes each delay by up to this fraction of its value,
+	// e.g. 0.2 waits between 80% and 120% of the computed delay.
+	Jitter float64
+	// Retryable reports whether a failed request should be retried.
+	// If nil, DefaultRetryable is used.
+	Retryable func(err error) bool
+}
+
+// DefaultRetryPolicy returns a policy that makes up to 5 attempts,
+// backing off exponentially from 250ms to at most 10s.
+func DefaultRetryPolicy() *RetryPolicy {
+	return &RetryPolicy{
+		MaxAttempts:    5,
+		InitialBackoff: 250 * time.Millisecond,
+		MaxBackoff:     10 * time.Second,
+		Multiplier:     2,
+		Jitter:         0.2,
+	}
+}
+
+// WithRetryPolicy configures the Client to retry failed requests
+// according to policy. By default requests are not retried.
+func WithRetryPolicy(policy *RetryPolicy) ClientOption {
+	return func(c *Client) {
+		c.retry = policy
+	}
+}
+
+// DefaultRetryable reports whether err is likely to be transient:
+// a 5xx or 429 response, a timeout, or a dropped or refused connection.
+// Parse errors and cancelled contexts are never retried.
+func DefaultRetryable(err error) bool {
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.Temporary()
+	}
+
+	var parseErr *ParseError
+	if errors.As(err, &parseErr) {
+		return false
+	}
+
+	if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) ||
+		errors.Is(err, syscall.ECONNRESET) || errors.Is(err, syscall.ECONNREFUSED) {
+		return true
+	}
+
+	var netErr net.Error
+	if errors.As(err, &netErr) {
+		return netErr.Timeout()
+	}
+
+	return false
+}
+
+func (p *RetryPolicy) retryable(err error) bool {
+	if p.Retryable != nil {
+		return p.Retryable(err)
+	}
+
+	return DefaultRetryable(err)
+}
+
+// delay returns the jittered wait before the given retry (starting at 1).
+func (p *RetryPolicy) delay(retry int) time.Duration {
+	d := float64(p.InitialBackoff)
+	for i := 1; i < retry; i++ {
+		d *= p.Multiplier
+		if p.MaxBackoff > 0 && d >= float64(p.MaxBackoff) {
+			break
+		}
+	}
+
+	if p.MaxBackoff > 0 && d > float64(p.MaxBackoff) {
+		d = float64(p.MaxBackoff)
+	}
+
+	if p.Jitter > 0 {
+		d *= 1 + p.Jitter*(2*rand.Float64()-1)
+	}
+
+	return time.Duration(d)
+}
+
+// withRetry calls fn until it succeeds, returns an error that is not
+// retryable, or the retry policy is exhausted.
+func (c *Client) withRetry(ctx context.Context, fn func() error) error {
+	policy := c.retry
+	for attempt := 1; ; attempt++ {
+		err := fn()
+		if err == nil || policy == nil || attempt >= policy.MaxAttempts ||
+			ctx.Err() != nil || !policy.retryable(err) {
+			return err
+		}
+
+		if err := policy.wait(ctx, attempt); err != nil {
+			return err
+		}
+	}
+}
+
+// wait sleeps before the given retry (starting at 1),
+// returning early with an error if ctx is done.
+func (p *RetryPolicy) wait(ctx context.Context, retry int) error {
+	timer := time.NewTimer(p.delay(retry))
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,110 @@
+package activetick
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func newFlakyServer(failures int, status int) (*httptest.Server, *int) {
+	attempts := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		if attempts <= failures {
+			http.Error(w, "failed", status)
+			return
+		}
+
+		fmt.Fprintln(w, "20101101093000,26.880000,26.900000,26.860000,26.890000,1175094")
+	}))
+
+	return server, &attempts
+}
+
+func testRetryPolicy() *RetryPolicy {
+	return &RetryPolicy{
+		MaxAttempts:    3,
+		InitialBackoff: time.Millisecond,
+		Multiplier:     2,
+	}
+}
+
+func TestRetrySucceeds(t *testing.T) {
+	server, attempts := newFlakyServer(2, http.StatusBadGateway)
+	defer server.Close()
+
+	client := NewClient(server.Client(), server.URL, WithRetryPolicy(testRetryPolicy()))
+	resp, err := client.GetBarData(&BarDataRequest{Symbol: "SPY"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(resp.Records) != 1 {
+		t.Errorf("Expected %d records, got %d", 1, len(resp.Records))
+	}
+
+	if *attempts != 3 {
+		t.Errorf("Expected %d attempts, got %d", 3, *attempts)
+	}
+}
+
+func TestRetryExhausted(t *testing.T) {
+	server, attempts := newFlakyServer(5, http.StatusServiceUnavailable)
+	defer server.Close()
+
+	client := NewClient(server.Client(), server.URL, WithRetryPolicy(testRetryPolicy()))
+	if _, err := client.GetBarData(&BarDataRequest{Symbol: "SPY"}); err == nil {
+		t.Fatal("Expected error after exhausting retries")
+	}
+
+	if *attempts != 3 {
+		t.Errorf("Expected %d attempts, got %d", 3, *attempts)
+	}
+}
+
+func TestRetryNotRetryable(t *testing.T) {
+	server, attempts := newFlakyServer(1, http.StatusBadRequest)
+	defer server.Close()
+
+	client := NewClient(server.Client(), server.URL, WithRetryPolicy(testRetryPolicy()))
+	if _, err := client.GetBarData(&BarDataRequest{Symbol: "SPY"}); err == nil {
+		t.Fatal("Expected error for bad request")
+	}
+
+	if *attempts != 1 {
+		t.Errorf("Expected %d attempts, got %d", 1, *attempts)
+	}
+}
+
+func TestRetryTickDataBody(t *testing.T) {
+	body := "T,20101101093000123,26.88,100,Q,0,0,0,0\nT,20101101093001456,26.89,200,Q,0,0,0,0\n"
+	attempts := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		if attempts == 1 {
+			// Drop the connection partway through the body.
+			w.Header().Set("Content-Length", fmt.Sprint(len(body)))
+			fmt.Fprint(w, body[:len(body)/2])
+			return
+		}
+
+		fmt.Fprint(w, body)
+	}))
+	defer server.Close()
+
+	client := NewClient(server.Client(), server.URL, WithRetryPolicy(testRetryPolicy()))
+	resp, err := client.GetTickData(&TickDataRequest{Symbol: "SPY", Trades: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(resp.Records) != 2 {
+		t.Errorf("Expected %d records, got %d", 2, len(resp.Records))
+	}
+
+	if attempts != 2 {
+		t.Errorf("Expected %d attempts, got %d", 2, attempts)
+	}
+}
@@ -0,0 +1,174 @@
+package activetick
+
+import (
+	"context"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// QuoteStreamHandler receives the records delivered by Client.StreamQuotes.
+// Handlers are called sequentially from the goroutine that
+// called StreamQuotes.
+type QuoteStreamHandler interface {
+	HandleTrade(record *TradeStreamRecord)
+	HandleQuote(record *QuoteStreamRecord)
+}
+
+// StreamQuotes subscribes to real-time trades and quotes for the requested
+// symbols. It keeps the connection to the /quoteStream endpoint open and
+// passes each record to handler until ctx is cancelled or the stream ends.
+//
+// When the stream is stopped by cancelling ctx, the context's error is returned.
+func (c *Client) StreamQuotes(ctx context.Context, req *QuoteStreamRequest, handler QuoteStreamHandler) error {
+	values := url.Values{}
+	values.Set("symbol", strings.Join(req.Symbols, " "))
+
+	resp, err := c.get(ctx, "/quoteStream", values)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	reader := csv.NewReader(resp.Body)
+	reader.FieldsPerRecord = -1
+	for i := 1; ; i++ {
+		row, err := reader.Read()
+		if err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			} else if err == io.EOF {
+				return nil
+			}
+
+			return err
+		}
+
+		if err := handleStreamRow(row, c.location, handler); err != nil {
+			return &ParseError{Row: i, Record: row, Err: err}
+		}
+	}
+}
+
+func handleStreamRow(row []string, loc *time.Location, handler QuoteStreamHandler) error {
+	switch TickType(row[0]) {
+	case TickTypeTrade:
+		record, err := parseTradeStream(row, loc)
+		if err != nil {
+			return err
+		}
+
+		handler.HandleTrade(record)
+	case TickTypeQuote:
+		record, err := parseQuoteStream(row, loc)
+		if err != nil {
+			return err
+		}
+
+		handler.HandleQuote(record)
+	}
+
+	// Other record types (such as subscription acknowledgements)
+	// carry no market data and are skipped.
+	return nil
+}
+
+func parseTradeStream(row []string, loc *time.Location) (*TradeStreamRecord, error) {
+	if len(row) != 11 {
+		return nil, fmt.Errorf("Expected %d rows, got %d: %v",
+			11, len(row), row)
+	}
+
+	flags, err := strconv.ParseInt(row[2], 10, 64)
+	if err != nil {
+		return nil, err
+	}
+
+	record := &TradeStreamRecord{
+		Symbol:       row[1],
+		Flags:        TradeFlag(flags),
+		LastExchange: Exchange(row[7]),
+	}
+
+	for i := 0; i < len(record.TradeConditions); i++ {
+		tc, err := strconv.ParseInt(row[i+3], 10, 64)
+		if err != nil {
+			return nil, err
+		}
+
+		record.TradeConditions[i] = TradeCondition(tc)
+	}
+
+	price, err := strconv.ParseFloat(row[8], 64)
+	if err != nil {
+		return nil, err
+	}
+
+	size, err := strconv.Atoi(row[9])
+	if err != nil {
+		return nil, err
+	}
+
+	t, err := parseTime(row[10], loc)
+	if err != nil {
+		return nil, err
+	}
+
+	record.LastPrice = price
+	record.LastSize = size
+	record.LastDate = t
+	return record, nil
+}
+
+func parseQuoteStream(row []string, loc *time.Location) (*QuoteStreamRecord, error) {
+	if len(row) != 10 {
+		return nil, fmt.Errorf("Expected %d rows, got %d: %v",
+			10, len(row), row)
+	}
+
+	cond, err := strconv.Atoi(row[2])
+	if err != nil {
+		return nil, err
+	}
+
+	bidPrice, err := strconv.ParseFloat(row[5], 64)
+	if err != nil {
+		return nil, err
+	}
+
+	askPrice, err := strconv.ParseFloat(row[6], 64)
+	if err != nil {
+		return nil, err
+	}
+
+	bidSize, err := strconv.Atoi(row[7])
+	if err != nil {
+		return nil, err
+	}
+
+	askSize, err := strconv.Atoi(row[8])
+	if err != nil {
+		return nil, err
+	}
+
+	t, err := parseTime(row[9], loc)
+	if err != nil {
+		return nil, err
+	}
+
+	return &QuoteStreamRecord{
+		Symbol:         row[1],
+		QuoteCondition: cond,
+		BidExchange:    Exchange(row[3]),
+		AskExchange:    Exchange(row[4]),
+		BidPrice:       bidPrice,
+		AskPrice:       askPrice,
+		BidSize:        bidSize,
+		AskSize:        askSize,
+		QuoteTime:      t,
+	}, nil
+}
@@ -0,0 +1,53 @@
+package activetick
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+)
+
+type countingHandler struct {
+	trades []*TradeStreamRecord
+	quotes []*QuoteStreamRecord
+}
+
+func (h *countingHandler) HandleTrade(record *TradeStreamRecord) {
+	h.trades = append(h.trades, record)
+}
+
+func (h *countingHandler) HandleQuote(record *QuoteStreamRecord) {
+	h.quotes = append(h.quotes, record)
+}
+
+func TestStreamQuotes(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/quoteStream" {
+			http.NotFound(w, r)
+			return
+		}
+
+		if symbols := r.URL.Query().Get("symbol"); symbols != "AAPL SPY" {
+			t.Errorf("Unexpected symbol parameter: %q", symbols)
+		}
+
+		http.ServeFile(w, r, filepath.Join("testdata", "quoteStreamResponse.csv"))
+	}))
+	defer server.Close()
+
+	client := NewClient(server.Client(), server.URL)
+	req := &QuoteStreamRequest{Symbols: []string{"AAPL", "SPY"}}
+	handler := &countingHandler{}
+	if err := client.StreamQuotes(context.Background(), req, handler); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(handler.trades) != 3 {
+		t.Errorf("Expected %d trades, got %d", 3, len(handler.trades))
+	}
+
+	if len(handler.quotes) != 2 {
+		t.Errorf("Expected %d quotes, got %d", 2, len(handler.quotes))
+	}
+}